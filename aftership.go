@@ -14,10 +14,14 @@ type Config struct {
 	AuthenticationType AuthenticationType
 
 	// apiSecret
-	// if AuthenticationType is RSA, use rsa private key
+	// if AuthenticationType is RSA, use the PEM encoded rsa private key, PKCS#1 or PKCS#8
 	// if AuthenticationType is AES, use aes api secret
 	APISecret string
 
+	// Signer signs the requests instead of the APISecret, such as with a key held by a KMS.
+	// The AuthenticationType and the APISecret are ignored when it is set.
+	Signer Signer
+
 	// BaseURL is the base URL of AfterShip API. Defaults to 'https://api.aftership.com/tracking/2023-10'
 	BaseURL string
 
@@ -26,6 +30,18 @@ type Config struct {
 
 	// HTTPClient is the HTTP client to use when making requests. Defaults to http.DefaultClient.
 	HTTPClient *http.Client
+
+	// RetryPolicy configures the retries of failed requests. Defaults to no retries.
+	RetryPolicy RetryPolicy
+
+	// RateLimitMode controls how requests are throttled by the rate limit reported by the API. Defaults to RateLimitDisabled.
+	RateLimitMode RateLimitMode
+
+	// Middlewares wrap every API call made by the client, the first one is the outermost.
+	Middlewares []Middleware
+
+	// Logger logs the requests sent by the client, with the API key, the signatures and the personal data redacted.
+	Logger Logger
 }
 
 // Client is the client for all AfterShip API calls
@@ -34,8 +50,10 @@ type Client struct {
 	Config Config
 	// The HTTP client to use when sending requests. Defaults to `http.DefaultClient`.
 	httpClient *http.Client
-	// Rate limit
-	rateLimit *RateLimit
+	// Rate limit state shared by all requests
+	limiter *rateLimiter
+	// The signer of the AuthenticationType and the APISecret, parsed again only when they change
+	signers signerCache
 }
 
 // NewClient returns the AfterShip client
@@ -44,10 +62,18 @@ func NewClient(cfg Config) (*Client, error) {
 		return nil, errors.New(errEmptyAPIKey)
 	}
 
-	if cfg.AuthenticationType == AES {
+	client := &Client{
+		limiter:    &rateLimiter{},
+		httpClient: http.DefaultClient,
+	}
+
+	if cfg.Signer == nil && (cfg.AuthenticationType == AES || cfg.AuthenticationType == RSA) {
 		if cfg.APISecret == "" {
 			return nil, errors.New(errEmptyAPISecret)
 		}
+		if _, err := client.signers.get(cfg.AuthenticationType, cfg.APISecret); err != nil {
+			return nil, err
+		}
 	}
 
 	if cfg.BaseURL == "" {
@@ -58,12 +84,7 @@ func NewClient(cfg Config) (*Client, error) {
 		cfg.UserAgentPrefix = "aftership-sdk-go"
 	}
 
-	client := &Client{
-		Config:     cfg,
-		rateLimit:  &RateLimit{},
-		httpClient: http.DefaultClient,
-	}
-
+	client.Config = cfg
 	if cfg.HTTPClient != nil {
 		client.httpClient = cfg.HTTPClient
 	}
@@ -71,7 +92,7 @@ func NewClient(cfg Config) (*Client, error) {
 	return client, nil
 }
 
-// GetRateLimit returns the X-RateLimit value in API response headers
+// GetRateLimit returns the X-RateLimit value in API response headers. It is safe for concurrent use.
 func (client *Client) GetRateLimit() RateLimit {
-	return *client.rateLimit
+	return client.limiter.snapshot()
 }
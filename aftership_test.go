@@ -43,6 +43,17 @@ func TestInvalidAPIKey(t *testing.T) {
 	assert.Equal(t, errEmptyAPIKey, err.Error())
 }
 
+func TestInvalidAPISecret(t *testing.T) {
+	// API Secret is not specified
+	_, err := NewClient(Config{APIKey: "YOUR_API_KEY", AuthenticationType: RSA})
+	assert.NotNil(t, err)
+	assert.Equal(t, errEmptyAPISecret, err.Error())
+
+	// API Secret is not an RSA private key
+	_, err = NewClient(Config{APIKey: "YOUR_API_KEY", AuthenticationType: RSA, APISecret: "YOUR_API_SECRET"})
+	assert.NotNil(t, err)
+}
+
 func TestDefaultConfig(t *testing.T) {
 	// API Key is specified
 	client, err := NewClient(Config{
@@ -0,0 +1,187 @@
+/*
+Package analytics derives delivery metrics from the checkpoints of the trackings,
+and aggregates them by courier and by lane.
+
+	metrics := make([]analytics.Metrics, 0, len(trackings))
+	for _, tracking := range trackings {
+		metrics = append(metrics, analytics.Compute(tracking))
+	}
+	for slug, summary := range analytics.BySlug(metrics) {
+		fmt.Println(slug, summary.MedianTransitTime, summary.FailedAttempts)
+	}
+*/
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+)
+
+// Lane is the origin and the destination countries of a tracking, as ISO 3166-1 alpha-3 codes
+type Lane struct {
+	Origin      string
+	Destination string
+}
+
+// String returns the lane as "USA→HKG"
+func (lane Lane) String() string {
+	return lane.Origin + "→" + lane.Destination
+}
+
+// Metrics are the metrics derived from the checkpoints of a tracking. The durations are zero when they are unknown.
+// The checkpoints without a zone offset are read in UTC, so the durations between checkpoints of different zones
+// may be off by the offset.
+type Metrics struct {
+	TrackingID string
+	Slug       string
+	Lane       Lane
+
+	// FirstScan is the time of the first checkpoint of the carrier, after the InfoReceived ones.
+	FirstScan time.Time
+
+	// DeliveredAt is the time of the first Delivered checkpoint, or the shipment delivery date.
+	DeliveredAt time.Time
+
+	// TimeToFirstScan is the time from the creation of the tracking to the first scan.
+	TimeToFirstScan time.Duration
+
+	// TransitTime is the time from the first scan to the delivery.
+	TransitTime time.Duration
+
+	// DwellByLocation is the time spent at every location, from a checkpoint to the next one.
+	// A location is the location of the checkpoint, or its city and country.
+	DwellByLocation map[string]time.Duration
+
+	// DwellByCountry is the time spent in every country, from a checkpoint to the next one.
+	DwellByCountry map[string]time.Duration
+
+	// CustomsHold is the time spent in customs, from a customs subtag (InTransit_006 or Exception_006)
+	// to the next checkpoint without a customs subtag, such as the clearance (InTransit_005).
+	// Many carriers don't send the clearance.
+	CustomsHold time.Duration
+
+	// FailedAttempts is the number of AttemptFail checkpoints.
+	FailedAttempts int
+
+	// ReportedTransitDays is the transit time in days reported by AfterShip.
+	ReportedTransitDays int
+
+	// TransitDaysMismatch is true if the transit time differs from the reported one by more than a day.
+	TransitDaysMismatch bool
+}
+
+// Delivered reports whether the tracking was delivered
+func (m Metrics) Delivered() bool {
+	return !m.DeliveredAt.IsZero()
+}
+
+// timedCheckpoint is a checkpoint with a known time
+type timedCheckpoint struct {
+	aftership.Checkpoint
+	time time.Time
+}
+
+// Compute derives the metrics of a tracking from its checkpoints
+func Compute(tracking aftership.Tracking) Metrics {
+	m := Metrics{
+		TrackingID:          tracking.ID,
+		Slug:                tracking.Slug,
+		Lane:                laneOf(tracking),
+		DwellByLocation:     make(map[string]time.Duration),
+		DwellByCountry:      make(map[string]time.Duration),
+		ReportedTransitDays: tracking.TransitTime,
+	}
+
+	checkpoints := make([]timedCheckpoint, 0, len(tracking.Checkpoints))
+	for _, checkpoint := range tracking.Checkpoints {
+		if t := checkpoint.CheckpointTime.Time(); !t.IsZero() {
+			checkpoints = append(checkpoints, timedCheckpoint{Checkpoint: checkpoint, time: t})
+		}
+	}
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].time.Before(checkpoints[j].time)
+	})
+
+	var holdStart time.Time
+	for i, checkpoint := range checkpoints {
+		if m.FirstScan.IsZero() && checkpoint.Tag != aftership.TagInfoReceived && checkpoint.Tag != aftership.TagPending {
+			m.FirstScan = checkpoint.time
+		}
+		if m.DeliveredAt.IsZero() && checkpoint.Tag == aftership.TagDelivered {
+			m.DeliveredAt = checkpoint.time
+		}
+		if checkpoint.Tag == aftership.TagAttemptFail {
+			m.FailedAttempts++
+		}
+
+		if i+1 < len(checkpoints) {
+			dwell := checkpoints[i+1].time.Sub(checkpoint.time)
+			if location := locationOf(checkpoint.Checkpoint); location != "" {
+				m.DwellByLocation[location] += dwell
+			}
+			if checkpoint.CountryISO3 != "" {
+				m.DwellByCountry[checkpoint.CountryISO3] += dwell
+			}
+		}
+
+		switch {
+		case isCustomsHold(checkpoint.Subtag) && holdStart.IsZero():
+			holdStart = checkpoint.time
+		case !isCustomsHold(checkpoint.Subtag) && !holdStart.IsZero():
+			m.CustomsHold += checkpoint.time.Sub(holdStart)
+			holdStart = time.Time{}
+		}
+	}
+	if !holdStart.IsZero() {
+		// Still held at the last checkpoint
+		m.CustomsHold += checkpoints[len(checkpoints)-1].time.Sub(holdStart)
+	}
+
+	if m.DeliveredAt.IsZero() {
+		m.DeliveredAt = tracking.ShipmentDeliveryDate.Time()
+	}
+	if !m.FirstScan.IsZero() && tracking.CreatedAt != nil && m.FirstScan.After(*tracking.CreatedAt) {
+		m.TimeToFirstScan = m.FirstScan.Sub(*tracking.CreatedAt)
+	}
+	if !m.FirstScan.IsZero() && m.DeliveredAt.After(m.FirstScan) {
+		m.TransitTime = m.DeliveredAt.Sub(m.FirstScan)
+
+		days := int(math.Ceil(m.TransitTime.Hours() / 24))
+		if m.ReportedTransitDays > 0 && (days-m.ReportedTransitDays > 1 || m.ReportedTransitDays-days > 1) {
+			m.TransitDaysMismatch = true
+		}
+	}
+
+	return m
+}
+
+// isCustomsHold reports whether the subtag starts a customs hold
+func isCustomsHold(subtag aftership.Subtag) bool {
+	return subtag == aftership.SubtagInTransit006 || subtag == aftership.SubtagException006
+}
+
+// laneOf returns the lane of a tracking, the destination being the one of the courier if there is none
+func laneOf(tracking aftership.Tracking) Lane {
+	destination := tracking.DestinationCountryISO3
+	if destination == "" {
+		destination = tracking.CourierDestinationCountryISO3
+	}
+	return Lane{Origin: tracking.OriginCountryISO3, Destination: destination}
+}
+
+// locationOf returns the location of a checkpoint, or its city and country
+func locationOf(checkpoint aftership.Checkpoint) string {
+	if checkpoint.Location != "" {
+		return checkpoint.Location
+	}
+	if checkpoint.City != "" && checkpoint.CountryISO3 != "" {
+		return checkpoint.City + ", " + checkpoint.CountryISO3
+	}
+	if checkpoint.City != "" {
+		return checkpoint.City
+	}
+	return checkpoint.CountryISO3
+}
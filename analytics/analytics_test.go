@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func localTime(t *testing.T, value string) aftership.LocalTime {
+	parsed, err := aftership.ParseLocalTime(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func testTracking(t *testing.T) aftership.Tracking {
+	createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return aftership.Tracking{
+		ID:                     "1",
+		Slug:                   "dhl",
+		OriginCountryISO3:      "HKG",
+		DestinationCountryISO3: "USA",
+		CreatedAt:              &createdAt,
+		TransitTime:            4,
+		Checkpoints: []aftership.Checkpoint{
+			{CheckpointTime: localTime(t, "2024-03-01T02:00:00Z"), Tag: aftership.TagInfoReceived, CountryISO3: "HKG"},
+			{CheckpointTime: localTime(t, "2024-03-01T06:00:00Z"), Tag: aftership.TagInTransit, City: "Hong Kong", CountryISO3: "HKG"},
+			{CheckpointTime: localTime(t, "2024-03-02T06:00:00Z"), Tag: aftership.TagInTransit, Location: "Cincinnati Hub", CountryISO3: "USA", Subtag: aftership.SubtagInTransit006, Message: "Held by Customs"},
+			{CheckpointTime: localTime(t, "2024-03-03T18:00:00Z"), Tag: aftership.TagInTransit, Location: "Cincinnati Hub", CountryISO3: "USA", Subtag: aftership.SubtagInTransit005, Message: "Customs clearance completed"},
+			{CheckpointTime: localTime(t, "2024-03-04T06:00:00Z"), Tag: aftership.TagAttemptFail, City: "New York", CountryISO3: "USA"},
+			{CheckpointTime: localTime(t, "2024-03-05T06:00:00Z"), Tag: aftership.TagDelivered, City: "New York", CountryISO3: "USA"},
+			{Tag: aftership.TagInTransit, Message: "no time"},
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	m := Compute(testTracking(t))
+
+	assert.Equal(t, "1", m.TrackingID)
+	assert.Equal(t, "dhl", m.Slug)
+	assert.Equal(t, Lane{Origin: "HKG", Destination: "USA"}, m.Lane)
+	assert.Equal(t, "HKG→USA", m.Lane.String())
+	assert.Equal(t, time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC), m.FirstScan)
+	assert.True(t, m.Delivered())
+	assert.Equal(t, time.Date(2024, 3, 5, 6, 0, 0, 0, time.UTC), m.DeliveredAt)
+	assert.Equal(t, 6*time.Hour, m.TimeToFirstScan)
+	assert.Equal(t, 96*time.Hour, m.TransitTime)
+	assert.Equal(t, map[string]time.Duration{
+		"HKG":            4 * time.Hour,
+		"Hong Kong, HKG": 24 * time.Hour,
+		"Cincinnati Hub": 48 * time.Hour,
+		"New York, USA":  24 * time.Hour,
+	}, m.DwellByLocation)
+	assert.Equal(t, map[string]time.Duration{
+		"HKG": 28 * time.Hour,
+		"USA": 72 * time.Hour,
+	}, m.DwellByCountry)
+	assert.Equal(t, 36*time.Hour, m.CustomsHold)
+	assert.Equal(t, 1, m.FailedAttempts)
+	assert.Equal(t, 4, m.ReportedTransitDays)
+	assert.False(t, m.TransitDaysMismatch)
+}
+
+func TestComputeUnsortedCheckpoints(t *testing.T) {
+	tracking := testTracking(t)
+	checkpoints := tracking.Checkpoints
+	for i, j := 0, len(checkpoints)-1; i < j; i, j = i+1, j-1 {
+		checkpoints[i], checkpoints[j] = checkpoints[j], checkpoints[i]
+	}
+
+	assert.Equal(t, Compute(testTracking(t)), Compute(tracking))
+}
+
+func TestComputeTransitDaysMismatch(t *testing.T) {
+	tracking := testTracking(t)
+	tracking.TransitTime = 10
+
+	m := Compute(tracking)
+	assert.True(t, m.TransitDaysMismatch)
+}
+
+func TestComputeNotDelivered(t *testing.T) {
+	tracking := testTracking(t)
+	tracking.Checkpoints = tracking.Checkpoints[:3]
+	tracking.DestinationCountryISO3 = ""
+	tracking.CourierDestinationCountryISO3 = "CAN"
+
+	m := Compute(tracking)
+	assert.False(t, m.Delivered())
+	assert.Equal(t, time.Duration(0), m.TransitTime)
+	assert.False(t, m.TransitDaysMismatch)
+	assert.Equal(t, Lane{Origin: "HKG", Destination: "CAN"}, m.Lane)
+}
+
+func TestComputeShipmentDeliveryDate(t *testing.T) {
+	tracking := testTracking(t)
+	tracking.Checkpoints = tracking.Checkpoints[:3]
+	tracking.ShipmentDeliveryDate = localTime(t, "2024-03-03T06:00:00Z")
+
+	m := Compute(tracking)
+	assert.True(t, m.Delivered())
+	assert.Equal(t, 48*time.Hour, m.TransitTime)
+}
+
+func TestComputeCustomsHold(t *testing.T) {
+	tracking := testTracking(t)
+
+	// The messages are not read
+	tracking.Checkpoints[1].Message = "Customs documents received"
+	tracking.Checkpoints[2].Subtag = aftership.SubtagInTransit003
+	m := Compute(tracking)
+	assert.Equal(t, time.Duration(0), m.CustomsHold)
+
+	// Exception hold, until the clearance
+	tracking.Checkpoints[2].Subtag = aftership.SubtagException006
+	m = Compute(tracking)
+	assert.Equal(t, 36*time.Hour, m.CustomsHold)
+
+	// Without clearance, the hold ends at the next checkpoint in transit
+	tracking.Checkpoints[3].Subtag = aftership.SubtagInTransit003
+	m = Compute(tracking)
+	assert.Equal(t, 36*time.Hour, m.CustomsHold)
+
+	// Or at the delivery
+	tracking.Checkpoints[3].Subtag = aftership.SubtagException006
+	tracking.Checkpoints = append(tracking.Checkpoints[:4], tracking.Checkpoints[5])
+	m = Compute(tracking)
+	assert.Equal(t, 72*time.Hour, m.CustomsHold)
+
+	// Still held at the last checkpoint
+	tracking.Checkpoints = tracking.Checkpoints[:4]
+	m = Compute(tracking)
+	assert.Equal(t, 36*time.Hour, m.CustomsHold)
+}
+
+func TestComputeEmpty(t *testing.T) {
+	m := Compute(aftership.Tracking{})
+	assert.False(t, m.Delivered())
+	assert.True(t, m.FirstScan.IsZero())
+	assert.Equal(t, time.Duration(0), m.TimeToFirstScan)
+	assert.Empty(t, m.DwellByLocation)
+	assert.Empty(t, m.DwellByCountry)
+}
@@ -0,0 +1,359 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+)
+
+// EstimateSource is the source of a delivery date estimate
+type EstimateSource string
+
+// Sources of the delivery date estimates
+const (
+	// EstimatePromised is the order promised delivery date
+	EstimatePromised EstimateSource = "promised"
+
+	// EstimateFirst is the first estimated delivery
+	EstimateFirst EstimateSource = "first"
+
+	// EstimateLatest is the latest estimated delivery
+	EstimateLatest EstimateSource = "latest"
+
+	// EstimateAfterShip is the AfterShip estimated delivery date
+	EstimateAfterShip EstimateSource = "aftership"
+)
+
+// estimateSources are the estimate sources in the order of the reports
+var estimateSources = []EstimateSource{EstimatePromised, EstimateFirst, EstimateLatest, EstimateAfterShip}
+
+// Report groups
+const (
+	GroupAll  = "all"
+	GroupSlug = "slug"
+	GroupLane = "lane"
+)
+
+// Accuracy compares the estimates of a source with the delivery dates. The dates are compared as days,
+// in the local time of the carrier.
+type Accuracy struct {
+	// Count is the number of delivered trackings with an estimate
+	Count int
+
+	// Early, Exact and Late are the number of trackings delivered before, on, and after the estimated day
+	Early int
+	Exact int
+	Late  int
+
+	// Ranges is the number of estimates with a range, and RangeHits the number of them delivered within the range
+	Ranges    int
+	RangeHits int
+
+	biasDays     int
+	absErrorDays int
+}
+
+// OnTimeRate returns the rate of the trackings delivered on or before the estimated day
+func (a Accuracy) OnTimeRate() float64 {
+	return rate(a.Early+a.Exact, a.Count)
+}
+
+// ExactRate returns the rate of the trackings delivered on the estimated day
+func (a Accuracy) ExactRate() float64 {
+	return rate(a.Exact, a.Count)
+}
+
+// RangeHitRate returns the rate of the estimates with a range delivered within the range
+func (a Accuracy) RangeHitRate() float64 {
+	return rate(a.RangeHits, a.Ranges)
+}
+
+// MeanBiasDays returns the mean number of days between the estimated and the delivery days,
+// positive when the trackings are delivered late
+func (a Accuracy) MeanBiasDays() float64 {
+	return rate(a.biasDays, a.Count)
+}
+
+// MeanAbsErrorDays returns the mean absolute number of days between the estimated and the delivery days
+func (a Accuracy) MeanAbsErrorDays() float64 {
+	return rate(a.absErrorDays, a.Count)
+}
+
+func (a *Accuracy) add(e estimate, delivered time.Time) {
+	if !e.date.IsZero() {
+		days := int(delivered.Sub(e.date).Hours() / 24)
+		a.Count++
+		a.biasDays += days
+		switch {
+		case days < 0:
+			a.Early++
+			a.absErrorDays -= days
+		case days > 0:
+			a.Late++
+			a.absErrorDays += days
+		default:
+			a.Exact++
+		}
+	}
+
+	if !e.min.IsZero() && !e.max.IsZero() {
+		a.Ranges++
+		if !delivered.Before(e.min) && !delivered.After(e.max) {
+			a.RangeHits++
+		}
+	}
+}
+
+// estimate is a delivery date estimate, as days
+type estimate struct {
+	date time.Time
+	min  time.Time
+	max  time.Time
+}
+
+// EDDReport compares the promised and the estimated delivery dates of trackings with their delivery dates,
+// overall, by courier and by lane.
+//
+//	report := analytics.NewEDDReport(trackings)
+//	fmt.Println(report.Overall[analytics.EstimateLatest].OnTimeRate())
+//	err := report.WriteCSV(os.Stdout)
+type EDDReport struct {
+	Overall map[EstimateSource]Accuracy
+	BySlug  map[string]map[EstimateSource]Accuracy
+	ByLane  map[Lane]map[EstimateSource]Accuracy
+}
+
+// NewEDDReport compares the estimates of the delivered trackings with their delivery dates.
+// The trackings which are not delivered are skipped.
+//
+// The estimate of the first, the latest and the AfterShip estimated deliveries is their date,
+// or the end of their range. The promised delivery date has no range.
+func NewEDDReport(trackings []aftership.Tracking) EDDReport {
+	report := EDDReport{
+		Overall: make(map[EstimateSource]Accuracy),
+		BySlug:  make(map[string]map[EstimateSource]Accuracy),
+		ByLane:  make(map[Lane]map[EstimateSource]Accuracy),
+	}
+
+	for _, tracking := range trackings {
+		delivered := deliveryDay(tracking)
+		if delivered.IsZero() {
+			continue
+		}
+
+		lane := laneOf(tracking)
+		if report.BySlug[tracking.Slug] == nil {
+			report.BySlug[tracking.Slug] = make(map[EstimateSource]Accuracy)
+		}
+		if report.ByLane[lane] == nil {
+			report.ByLane[lane] = make(map[EstimateSource]Accuracy)
+		}
+
+		for source, e := range estimatesOf(tracking) {
+			addAccuracy(report.Overall, source, e, delivered)
+			addAccuracy(report.BySlug[tracking.Slug], source, e, delivered)
+			addAccuracy(report.ByLane[lane], source, e, delivered)
+		}
+	}
+	return report
+}
+
+func addAccuracy(accuracies map[EstimateSource]Accuracy, source EstimateSource, e estimate, delivered time.Time) {
+	accuracy := accuracies[source]
+	accuracy.add(e, delivered)
+	accuracies[source] = accuracy
+}
+
+// EDDRow is a row of the EDD report, the accuracy of a source for a group of trackings
+type EDDRow struct {
+	Group            string         `json:"group"`
+	Key              string         `json:"key"`
+	Source           EstimateSource `json:"source"`
+	Count            int            `json:"count"`
+	Early            int            `json:"early"`
+	Exact            int            `json:"exact"`
+	Late             int            `json:"late"`
+	OnTimeRate       float64        `json:"on_time_rate"`
+	ExactRate        float64        `json:"exact_rate"`
+	MeanBiasDays     float64        `json:"mean_bias_days"`
+	MeanAbsErrorDays float64        `json:"mean_abs_error_days"`
+	Ranges           int            `json:"ranges"`
+	RangeHits        int            `json:"range_hits"`
+	RangeHitRate     float64        `json:"range_hit_rate"`
+}
+
+// Rows returns the rows of the report, overall first, then by courier and by lane, in the order of their keys.
+// The sources without estimates are skipped.
+func (r EDDReport) Rows() []EDDRow {
+	var rows []EDDRow
+	rows = appendRows(rows, GroupAll, "", r.Overall)
+
+	slugs := make([]string, 0, len(r.BySlug))
+	for slug := range r.BySlug {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	for _, slug := range slugs {
+		rows = appendRows(rows, GroupSlug, slug, r.BySlug[slug])
+	}
+
+	lanes := make([]Lane, 0, len(r.ByLane))
+	for lane := range r.ByLane {
+		lanes = append(lanes, lane)
+	}
+	sort.Slice(lanes, func(i, j int) bool {
+		return lanes[i].String() < lanes[j].String()
+	})
+	for _, lane := range lanes {
+		rows = appendRows(rows, GroupLane, lane.String(), r.ByLane[lane])
+	}
+	return rows
+}
+
+func appendRows(rows []EDDRow, group, key string, accuracies map[EstimateSource]Accuracy) []EDDRow {
+	for _, source := range estimateSources {
+		a, ok := accuracies[source]
+		if !ok {
+			continue
+		}
+		rows = append(rows, EDDRow{
+			Group:            group,
+			Key:              key,
+			Source:           source,
+			Count:            a.Count,
+			Early:            a.Early,
+			Exact:            a.Exact,
+			Late:             a.Late,
+			OnTimeRate:       a.OnTimeRate(),
+			ExactRate:        a.ExactRate(),
+			MeanBiasDays:     a.MeanBiasDays(),
+			MeanAbsErrorDays: a.MeanAbsErrorDays(),
+			Ranges:           a.Ranges,
+			RangeHits:        a.RangeHits,
+			RangeHitRate:     a.RangeHitRate(),
+		})
+	}
+	return rows
+}
+
+// eddCSVHeader is the header of the CSV report
+var eddCSVHeader = []string{
+	"group", "key", "source", "count", "early", "exact", "late", "on_time_rate", "exact_rate",
+	"mean_bias_days", "mean_abs_error_days", "ranges", "range_hits", "range_hit_rate",
+}
+
+// WriteCSV writes the rows of the report as CSV, with a header
+func (r EDDReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(eddCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range r.Rows() {
+		record := []string{
+			row.Group,
+			row.Key,
+			string(row.Source),
+			strconv.Itoa(row.Count),
+			strconv.Itoa(row.Early),
+			strconv.Itoa(row.Exact),
+			strconv.Itoa(row.Late),
+			formatFloat(row.OnTimeRate),
+			formatFloat(row.ExactRate),
+			formatFloat(row.MeanBiasDays),
+			formatFloat(row.MeanAbsErrorDays),
+			strconv.Itoa(row.Ranges),
+			strconv.Itoa(row.RangeHits),
+			formatFloat(row.RangeHitRate),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the rows of the report as a JSON array
+func (r EDDReport) WriteJSON(w io.Writer) error {
+	rows := r.Rows()
+	if rows == nil {
+		rows = []EDDRow{}
+	}
+	return json.NewEncoder(w).Encode(rows)
+}
+
+// estimatesOf returns the estimates of a tracking, by source
+func estimatesOf(tracking aftership.Tracking) map[EstimateSource]estimate {
+	estimates := make(map[EstimateSource]estimate)
+	add := func(source EstimateSource, e estimate) {
+		if e.date.IsZero() && e.max.IsZero() {
+			return
+		}
+		if e.date.IsZero() {
+			e.date = e.max
+		}
+		estimates[source] = e
+	}
+
+	add(EstimatePromised, estimate{date: parseDay(tracking.OrderPromisedDeliveryDate)})
+	add(EstimateFirst, estimatedDelivery(tracking.FirstEstimatedDelivery))
+	add(EstimateLatest, estimatedDelivery(tracking.LatestEstimatedDelivery))
+	add(EstimateAfterShip, estimate{
+		date: parseDay(tracking.EstimatedDeliveryDate.EstimatedDeliveryDate),
+		min:  parseDay(tracking.EstimatedDeliveryDate.EstimatedDeliveryDateMin),
+		max:  parseDay(tracking.EstimatedDeliveryDate.EstimatedDeliveryDateMax),
+	})
+	return estimates
+}
+
+func estimatedDelivery(delivery aftership.EstimatedDelivery) estimate {
+	return estimate{
+		date: parseDay(delivery.Datetime),
+		min:  parseDay(delivery.DatetimeMin),
+		max:  parseDay(delivery.DatetimeMax),
+	}
+}
+
+// deliveryDay returns the day of the shipment delivery date, or of the first Delivered checkpoint
+func deliveryDay(tracking aftership.Tracking) time.Time {
+	if !tracking.ShipmentDeliveryDate.IsZero() {
+		return dayOf(tracking.ShipmentDeliveryDate)
+	}
+	for _, checkpoint := range tracking.Checkpoints {
+		if checkpoint.Tag == aftership.TagDelivered && !checkpoint.CheckpointTime.IsZero() {
+			return dayOf(checkpoint.CheckpointTime)
+		}
+	}
+	return time.Time{}
+}
+
+// parseDay returns the day of a time sent by the API, or the zero time if it can't be parsed
+func parseDay(value string) time.Time {
+	t, err := aftership.ParseLocalTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return dayOf(t)
+}
+
+// dayOf returns the day of a time in the local time of the carrier, as midnight UTC
+func dayOf(t aftership.LocalTime) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
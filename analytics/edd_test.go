@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func eddTrackings(t *testing.T) []aftership.Tracking {
+	return []aftership.Tracking{
+		{
+			Slug:                      "dhl",
+			OriginCountryISO3:         "HKG",
+			DestinationCountryISO3:    "USA",
+			ShipmentDeliveryDate:      localTime(t, "2024-03-05T22:00:00-05:00"),
+			OrderPromisedDeliveryDate: "2024-03-06",
+			FirstEstimatedDelivery: aftership.EstimatedDelivery{
+				Type:        "range",
+				DatetimeMin: "2024-03-01",
+				DatetimeMax: "2024-03-03",
+			},
+			LatestEstimatedDelivery: aftership.EstimatedDelivery{
+				Type:     "specific",
+				Datetime: "2024-03-05T12:00:00-05:00",
+			},
+			EstimatedDeliveryDate: aftership.EstimatedDeliveryDate{
+				EstimatedDeliveryDate:    "2024-03-04",
+				EstimatedDeliveryDateMin: "2024-03-04",
+				EstimatedDeliveryDateMax: "2024-03-06",
+			},
+		},
+		{
+			Slug:                      "ups",
+			OriginCountryISO3:         "USA",
+			DestinationCountryISO3:    "USA",
+			OrderPromisedDeliveryDate: "2024-03-04",
+			LatestEstimatedDelivery: aftership.EstimatedDelivery{
+				Datetime: "2024-03-07",
+			},
+			Checkpoints: []aftership.Checkpoint{
+				{CheckpointTime: localTime(t, "2024-03-02T10:00:00"), Tag: aftership.TagInTransit},
+				{CheckpointTime: localTime(t, "2024-03-06T10:00:00"), Tag: aftership.TagDelivered},
+			},
+		},
+		{
+			Slug:                      "dhl",
+			OriginCountryISO3:         "HKG",
+			DestinationCountryISO3:    "USA",
+			OrderPromisedDeliveryDate: "2024-03-04",
+		},
+	}
+}
+
+func TestNewEDDReport(t *testing.T) {
+	report := NewEDDReport(eddTrackings(t))
+
+	promised := report.Overall[EstimatePromised]
+	assert.Equal(t, 2, promised.Count)
+	assert.Equal(t, 1, promised.Early)
+	assert.Equal(t, 1, promised.Late)
+	assert.Equal(t, 0.5, promised.OnTimeRate())
+	assert.Equal(t, 0.5, promised.MeanBiasDays())
+	assert.Equal(t, 1.5, promised.MeanAbsErrorDays())
+	assert.Equal(t, 0, promised.Ranges)
+
+	first := report.Overall[EstimateFirst]
+	assert.Equal(t, 1, first.Count)
+	assert.Equal(t, 1, first.Late)
+	assert.Equal(t, 2.0, first.MeanBiasDays())
+	assert.Equal(t, 1, first.Ranges)
+	assert.Equal(t, 0, first.RangeHits)
+
+	latest := report.Overall[EstimateLatest]
+	assert.Equal(t, 2, latest.Count)
+	assert.Equal(t, 1, latest.Exact)
+	assert.Equal(t, 1, latest.Early)
+	assert.Equal(t, 1.0, latest.OnTimeRate())
+	assert.Equal(t, 0.5, latest.ExactRate())
+
+	edd := report.Overall[EstimateAfterShip]
+	assert.Equal(t, 1, edd.Count)
+	assert.Equal(t, 1, edd.Late)
+	assert.Equal(t, 1, edd.RangeHits)
+	assert.Equal(t, 1.0, edd.RangeHitRate())
+
+	assert.Len(t, report.BySlug, 2)
+	assert.Equal(t, 1, report.BySlug["dhl"][EstimatePromised].Count)
+	assert.Equal(t, 1, report.BySlug["ups"][EstimatePromised].Count)
+	assert.Len(t, report.ByLane, 2)
+	assert.Equal(t, 1, report.ByLane[Lane{Origin: "USA", Destination: "USA"}][EstimateLatest].Count)
+}
+
+func TestEDDReportRows(t *testing.T) {
+	rows := NewEDDReport(eddTrackings(t)).Rows()
+
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.Group+"/"+row.Key+"/"+string(row.Source))
+	}
+	assert.Equal(t, []string{
+		"all//promised", "all//first", "all//latest", "all//aftership",
+		"slug/dhl/promised", "slug/dhl/first", "slug/dhl/latest", "slug/dhl/aftership",
+		"slug/ups/promised", "slug/ups/latest",
+		"lane/HKG→USA/promised", "lane/HKG→USA/first", "lane/HKG→USA/latest", "lane/HKG→USA/aftership",
+		"lane/USA→USA/promised", "lane/USA→USA/latest",
+	}, keys)
+}
+
+func TestEDDReportWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewEDDReport(eddTrackings(t)[1:2]).WriteCSV(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "group,key,source,count,early,exact,late,on_time_rate,exact_rate,mean_bias_days,mean_abs_error_days,ranges,range_hits,range_hit_rate\n"+
+		"all,,promised,1,0,0,1,0.0000,0.0000,2.0000,2.0000,0,0,0.0000\n"+
+		"all,,latest,1,1,0,0,1.0000,0.0000,-1.0000,1.0000,0,0,0.0000\n"+
+		"slug,ups,promised,1,0,0,1,0.0000,0.0000,2.0000,2.0000,0,0,0.0000\n"+
+		"slug,ups,latest,1,1,0,0,1.0000,0.0000,-1.0000,1.0000,0,0,0.0000\n"+
+		"lane,USA→USA,promised,1,0,0,1,0.0000,0.0000,2.0000,2.0000,0,0,0.0000\n"+
+		"lane,USA→USA,latest,1,1,0,0,1.0000,0.0000,-1.0000,1.0000,0,0,0.0000\n", buf.String())
+}
+
+func TestEDDReportWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewEDDReport(eddTrackings(t)[1:2]).WriteJSON(&buf)
+	assert.Nil(t, err)
+
+	var rows []EDDRow
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Len(t, rows, 6)
+	assert.Equal(t, EDDRow{
+		Group:            GroupAll,
+		Source:           EstimateLatest,
+		Count:            1,
+		Early:            1,
+		OnTimeRate:       1,
+		MeanBiasDays:     -1,
+		MeanAbsErrorDays: 1,
+	}, rows[1])
+
+	buf.Reset()
+	err = NewEDDReport(nil).WriteJSON(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+// Summary aggregates the metrics of trackings. The durations are computed over the trackings where they are known.
+type Summary struct {
+	Count     int
+	Delivered int
+
+	MeanTransitTime   time.Duration
+	MedianTransitTime time.Duration
+	P90TransitTime    time.Duration
+
+	MeanTimeToFirstScan time.Duration
+	MeanCustomsHold     time.Duration
+
+	// FailedAttempts is the total number of failed delivery attempts.
+	FailedAttempts int
+
+	// TransitDaysMismatches is the number of trackings whose transit time differs from the reported one.
+	TransitDaysMismatches int
+}
+
+// Summarize aggregates the metrics of trackings
+func Summarize(metrics []Metrics) Summary {
+	var summary Summary
+	var transitTimes, firstScans, customsHolds []time.Duration
+
+	for _, m := range metrics {
+		summary.Count++
+		if m.Delivered() {
+			summary.Delivered++
+		}
+		if m.TransitTime > 0 {
+			transitTimes = append(transitTimes, m.TransitTime)
+		}
+		if m.TimeToFirstScan > 0 {
+			firstScans = append(firstScans, m.TimeToFirstScan)
+		}
+		if m.CustomsHold > 0 {
+			customsHolds = append(customsHolds, m.CustomsHold)
+		}
+		summary.FailedAttempts += m.FailedAttempts
+		if m.TransitDaysMismatch {
+			summary.TransitDaysMismatches++
+		}
+	}
+
+	sort.Slice(transitTimes, func(i, j int) bool { return transitTimes[i] < transitTimes[j] })
+	summary.MeanTransitTime = mean(transitTimes)
+	summary.MedianTransitTime = percentile(transitTimes, 50)
+	summary.P90TransitTime = percentile(transitTimes, 90)
+	summary.MeanTimeToFirstScan = mean(firstScans)
+	summary.MeanCustomsHold = mean(customsHolds)
+	return summary
+}
+
+// BySlug aggregates the metrics of trackings by courier
+func BySlug(metrics []Metrics) map[string]Summary {
+	groups := make(map[string][]Metrics)
+	for _, m := range metrics {
+		groups[m.Slug] = append(groups[m.Slug], m)
+	}
+
+	summaries := make(map[string]Summary, len(groups))
+	for slug, group := range groups {
+		summaries[slug] = Summarize(group)
+	}
+	return summaries
+}
+
+// ByLane aggregates the metrics of trackings by lane
+func ByLane(metrics []Metrics) map[Lane]Summary {
+	groups := make(map[Lane][]Metrics)
+	for _, m := range metrics {
+		groups[m.Lane] = append(groups[m.Lane], m)
+	}
+
+	summaries := make(map[Lane]Summary, len(groups))
+	for lane, group := range groups {
+		summaries[lane] = Summarize(group)
+	}
+	return summaries
+}
+
+func mean(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
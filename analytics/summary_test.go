@@ -0,0 +1,64 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	metrics := []Metrics{
+		{TransitTime: 4 * time.Hour, DeliveredAt: time.Now(), TimeToFirstScan: time.Hour, CustomsHold: 2 * time.Hour},
+		{TransitTime: 2 * time.Hour, DeliveredAt: time.Now(), TimeToFirstScan: 3 * time.Hour, FailedAttempts: 2},
+		{TransitTime: 6 * time.Hour, DeliveredAt: time.Now(), FailedAttempts: 1, TransitDaysMismatch: true},
+		{},
+	}
+
+	summary := Summarize(metrics)
+	assert.Equal(t, Summary{
+		Count:                 4,
+		Delivered:             3,
+		MeanTransitTime:       4 * time.Hour,
+		MedianTransitTime:     4 * time.Hour,
+		P90TransitTime:        6 * time.Hour,
+		MeanTimeToFirstScan:   2 * time.Hour,
+		MeanCustomsHold:       2 * time.Hour,
+		FailedAttempts:        3,
+		TransitDaysMismatches: 1,
+	}, summary)
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	assert.Equal(t, Summary{}, Summarize(nil))
+}
+
+func TestBySlug(t *testing.T) {
+	metrics := []Metrics{
+		{Slug: "dhl", TransitTime: time.Hour},
+		{Slug: "ups", TransitTime: 2 * time.Hour},
+		{Slug: "dhl", TransitTime: 3 * time.Hour},
+	}
+
+	summaries := BySlug(metrics)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 2, summaries["dhl"].Count)
+	assert.Equal(t, 2*time.Hour, summaries["dhl"].MeanTransitTime)
+	assert.Equal(t, 1, summaries["ups"].Count)
+}
+
+func TestByLane(t *testing.T) {
+	usa := Lane{Origin: "HKG", Destination: "USA"}
+	can := Lane{Origin: "HKG", Destination: "CAN"}
+	metrics := []Metrics{
+		{Lane: usa, FailedAttempts: 1},
+		{Lane: can},
+		{Lane: usa, FailedAttempts: 2},
+	}
+
+	summaries := ByLane(metrics)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 2, summaries[usa].Count)
+	assert.Equal(t, 3, summaries[usa].FailedAttempts)
+	assert.Equal(t, 1, summaries[can].Count)
+}
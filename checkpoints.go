@@ -27,8 +27,8 @@ type LastCheckpoint struct {
 	ID             string     `json:"id,omitempty"`
 	Slug           string     `json:"slug,omitempty"`
 	TrackingNumber string     `json:"tracking_number,omitempty"`
-	Tag            string     `json:"tag,omitempty"`
-	Subtag         string     `json:"subtag,omitempty"`
+	Tag            Tag        `json:"tag,omitempty"`
+	Subtag         Subtag     `json:"subtag,omitempty"`
 	SubtagMessage  string     `json:"subtag_message,omitempty"`
 	Checkpoint     Checkpoint `json:"checkpoint"`
 }
@@ -42,6 +42,6 @@ func (client *Client) GetLastCheckpoint(ctx context.Context, identifier Tracking
 
 	uriPath = fmt.Sprintf("/last_checkpoint%s", uriPath)
 	var lastCheckpoint LastCheckpoint
-	err = client.makeRequest(ctx, http.MethodGet, uriPath, params, nil, &lastCheckpoint)
+	err = client.makeRequest(withOperation(ctx, "GetLastCheckpoint", slugOf(identifier)), http.MethodGet, uriPath, params, nil, &lastCheckpoint)
 	return lastCheckpoint, err
 }
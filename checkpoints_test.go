@@ -65,7 +65,7 @@ func TestGetLastCheckpoint(t *testing.T) {
 		Checkpoint: Checkpoint{
 			Slug:           "fedex",
 			CreatedAt:      &createdAt,
-			CheckpointTime: "2018-08-01T13:19:47-04:00",
+			CheckpointTime: mustParseLocalTime("2018-08-01T13:19:47-04:00"),
 			City:           "Deal",
 			Coordinates:    []float32{},
 			Message:        "Delivered - Left at front door. Signature Service not requested.",
@@ -0,0 +1,58 @@
+package aftership
+
+import (
+	"context"
+	"net/http"
+)
+
+// headerKey is the context key of the extra headers
+type headerKey struct{}
+
+// WithRequestID returns a copy of ctx sending id as the request-id header of the API calls made with it,
+// instead of a generated one. The id is kept across retries.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return WithHeader(ctx, "request-id", id)
+}
+
+// WithHeader returns a copy of ctx sending an extra header with the API calls made with it,
+// such as an "as-store-id" header. The "as-" prefixed headers are included in the request signature.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	header := headerFromContext(ctx)
+	header.Set(key, value)
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+// responseKey is the context key of the response target
+type responseKey struct{}
+
+// WithResponse returns a copy of ctx capturing the response envelope of the API call made with it into resp,
+// including the meta, the status code, the headers and the rate limit. resp is set even when the API
+// responds with an error, and left unchanged when no response could be decoded.
+func WithResponse(ctx context.Context, resp *Response) context.Context {
+	return context.WithValue(ctx, responseKey{}, resp)
+}
+
+// captureResponse copies resp to the response target of ctx, if there is one.
+func captureResponse(ctx context.Context, resp *Response) {
+	if target, ok := ctx.Value(responseKey{}).(*Response); ok && target != nil && resp != nil {
+		*target = *resp
+	}
+}
+
+// headerFromContext returns a copy of the extra headers carried by ctx.
+func headerFromContext(ctx context.Context) http.Header {
+	if header, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		return header.Clone()
+	}
+	return http.Header{}
+}
+
+// responseRequestID returns the request id echoed by the API, or an empty string if there is none.
+func responseRequestID(resp *http.Response) string {
+	for _, key := range []string{"request-id", "x-request-id"} {
+		if id := resp.Header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRequestID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	attempts := 0
+	mux.HandleFunc("/couriers", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		assert.Equal(t, "order-1234", r.Header.Get("request-id"))
+		assert.Equal(t, "store-1", r.Header.Get("as-store-id"))
+		assert.Equal(t, "YOUR_API_KEY", r.Header.Get("as-api-key"))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"meta": {"code": 503, "type": "ServiceUnavailable"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"couriers": []}}`))
+	})
+
+	var resp *Response
+	client.Config.RetryPolicy = RetryPolicy{MaxAttempts: 2, BaseDelay: 1}
+	client.Config.Middlewares = []Middleware{
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				assert.Equal(t, "order-1234", req.Header.Get("request-id"))
+				var err error
+				resp, err = next(ctx, req)
+				return resp, err
+			}
+		},
+	}
+
+	ctx := WithRequestID(context.Background(), "order-1234")
+	ctx = WithHeader(ctx, "as-store-id", "store-1")
+	_, err := client.GetCouriers(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "order-1234", resp.RequestID)
+}
+
+func TestWithHeaderDoesNotLeak(t *testing.T) {
+	ctx := WithHeader(context.Background(), "as-store-id", "store-1")
+	child := WithHeader(ctx, "as-store-id", "store-2")
+
+	assert.Equal(t, "store-1", headerFromContext(ctx).Get("as-store-id"))
+	assert.Equal(t, "store-2", headerFromContext(child).Get("as-store-id"))
+
+	header := headerFromContext(ctx)
+	header.Set("as-store-id", "store-3")
+	assert.Equal(t, "store-1", headerFromContext(ctx).Get("as-store-id"))
+	assert.Empty(t, headerFromContext(context.Background()))
+}
+
+func TestEchoedRequestID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings/5b7658cec7c33c0e007de3c5", func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("request-id"))
+		w.Header().Set("x-request-id", "server-id")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{
+			"meta": {"code": 4004, "type": "NotFound", "message": "Tracking does not exist."},
+			"data": {}
+		}`))
+	})
+
+	var resp Response
+	ctx := WithResponse(WithRequestID(context.Background(), "order-1234"), &resp)
+	_, err := client.GetTracking(ctx, TrackingID("5b7658cec7c33c0e007de3c5"), GetTrackingParams{})
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "order-1234", apiErr.RequestID)
+	assert.Equal(t, "server-id", apiErr.EchoedRequestID)
+	assert.Equal(t, "order-1234", resp.RequestID)
+	assert.Equal(t, "server-id", resp.EchoedRequestID)
+}
+
+func TestWithResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/couriers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-reset", "1458463600")
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "9")
+		w.Write([]byte(`{
+			"meta": {"code": 200, "message": "Deprecated soon.", "type": "OK"},
+			"data": {"couriers": []}
+		}`))
+	})
+
+	var resp Response
+	_, err := client.GetCouriers(WithResponse(context.Background(), &resp))
+	assert.Nil(t, err)
+	assert.Equal(t, Meta{Code: 200, Message: "Deprecated soon.", Type: "OK"}, resp.Meta)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "9", resp.Header.Get("x-ratelimit-remaining"))
+	assert.Equal(t, RateLimit{Reset: 1458463600, Limit: 10, Remaining: 9}, resp.RateLimit)
+	assert.NotEmpty(t, resp.RequestID)
+	assert.True(t, resp.Latency > 0)
+}
+
+func TestWithResponseOnError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings/5b7658cec7c33c0e007de3c5", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{
+			"meta": {"code": 4004, "type": "NotFound", "message": "Tracking does not exist."},
+			"data": {}
+		}`))
+	})
+
+	var resp Response
+	ctx := WithResponse(context.Background(), &resp)
+	_, err := client.GetTracking(ctx, TrackingID("5b7658cec7c33c0e007de3c5"), GetTrackingParams{})
+	assert.True(t, errors.Is(err, ErrTrackingNotFound))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Tracking does not exist.", resp.Meta.Message)
+
+	// No response is decoded on transport failures
+	resp = Response{}
+	client.Config.BaseURL = "http://127.0.0.1:0"
+	_, err = client.GetTracking(ctx, TrackingID("5b7658cec7c33c0e007de3c5"), GetTrackingParams{})
+	assert.NotNil(t, err)
+	assert.Equal(t, Response{}, resp)
+}
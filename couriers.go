@@ -45,14 +45,14 @@ type CourierDetectionParams struct {
 // GetCouriers returns a list of couriers activated at your AfterShip account.
 func (client *Client) GetCouriers(ctx context.Context) (CourierList, error) {
 	var courierList CourierList
-	err := client.makeRequest(ctx, http.MethodGet, "/couriers", nil, nil, &courierList)
+	err := client.makeRequest(withOperation(ctx, "GetCouriers", ""), http.MethodGet, "/couriers", nil, nil, &courierList)
 	return courierList, err
 }
 
 // GetAllCouriers returns a list of all couriers.
 func (client *Client) GetAllCouriers(ctx context.Context) (CourierList, error) {
 	var courierList CourierList
-	err := client.makeRequest(ctx, http.MethodGet, "/couriers/all", nil, nil, &courierList)
+	err := client.makeRequest(withOperation(ctx, "GetAllCouriers", ""), http.MethodGet, "/couriers/all", nil, nil, &courierList)
 	return courierList, err
 }
 
@@ -69,7 +69,7 @@ func (client *Client) DetectCouriers(ctx context.Context, params CourierDetectio
 	}
 
 	var courierList CourierList
-	err := client.makeRequest(ctx, http.MethodPost, "/couriers/detect", nil,
+	err := client.makeRequest(withOperation(ctx, "DetectCouriers", ""), http.MethodPost, "/couriers/detect", nil,
 		&detectCourierRequest{
 			Tracking: params,
 		}, &courierList)
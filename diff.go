@@ -0,0 +1,235 @@
+package aftership
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldChange is a change of a field of a tracking, or of a custom field.
+// Old is nil when the custom field is added, and New is nil when it is removed.
+type FieldChange struct {
+	// Field is the JSON name of the field, or the key of the custom field
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// ListChange is the values added to and removed from a list
+type ListChange struct {
+	Added   []string
+	Removed []string
+}
+
+// IsEmpty reports whether the list is unchanged
+func (c ListChange) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// TrackingDiff is the changes between two snapshots of a tracking
+type TrackingDiff struct {
+	// Fields are the changes of the scalar fields, in the order of the Tracking fields.
+	// The scalar fields are the strings, numbers, booleans, tags and times.
+	Fields []FieldChange
+
+	// AddedCheckpoints and RemovedCheckpoints are matched by time, message and location
+	AddedCheckpoints   []Checkpoint
+	RemovedCheckpoints []Checkpoint
+
+	Emails           ListChange
+	SMSes            ListChange
+	SubscribedEmails ListChange
+	SubscribedSMSes  ListChange
+
+	// CustomFields are the changes of the custom fields, in the order of their keys
+	CustomFields []FieldChange
+}
+
+// IsEmpty reports whether the tracking is unchanged
+func (d TrackingDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 &&
+		len(d.AddedCheckpoints) == 0 && len(d.RemovedCheckpoints) == 0 &&
+		d.Emails.IsEmpty() && d.SMSes.IsEmpty() &&
+		d.SubscribedEmails.IsEmpty() && d.SubscribedSMSes.IsEmpty() &&
+		len(d.CustomFields) == 0
+}
+
+// Field returns the change of a scalar field by its JSON name, such as "tag"
+func (d TrackingDiff) Field(name string) (FieldChange, bool) {
+	for _, change := range d.Fields {
+		if change.Field == name {
+			return change, true
+		}
+	}
+	return FieldChange{}, false
+}
+
+// DiffTrackings returns the changes between two snapshots of a tracking, such as before and after a GetTracking refresh
+func DiffTrackings(old, new Tracking) TrackingDiff {
+	diff := TrackingDiff{
+		Emails:           diffLists(old.Emails, new.Emails),
+		SMSes:            diffLists(old.SMSes, new.SMSes),
+		SubscribedEmails: diffLists(old.SubscribedEmails, new.SubscribedEmails),
+		SubscribedSMSes:  diffLists(old.SubscribedSMSes, new.SubscribedSMSes),
+		CustomFields:     diffCustomFields(old.CustomFields, new.CustomFields),
+	}
+	diff.Fields = diffFields(reflect.ValueOf(old), reflect.ValueOf(new), diff.Fields)
+	diff.AddedCheckpoints = subtractCheckpoints(new.Checkpoints, old.Checkpoints)
+	diff.RemovedCheckpoints = subtractCheckpoints(old.Checkpoints, new.Checkpoints)
+	return diff
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timePtrType   = reflect.TypeOf(&time.Time{})
+	localTimeType = reflect.TypeOf(LocalTime{})
+)
+
+// diffFields appends the changes of the scalar fields of two structs, including their embedded structs
+func diffFields(old, new reflect.Value, changes []FieldChange) []FieldChange {
+	typ := old.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			changes = diffFields(old.Field(i), new.Field(i), changes)
+			continue
+		}
+
+		oldValue, newValue := old.Field(i), new.Field(i)
+		equal, ok := scalarEqual(oldValue, newValue)
+		if ok && !equal {
+			changes = append(changes, FieldChange{
+				Field: jsonName(field),
+				Old:   oldValue.Interface(),
+				New:   newValue.Interface(),
+			})
+		}
+	}
+	return changes
+}
+
+// scalarEqual compares two values of a scalar type. It returns false as second value if the type isn't scalar.
+func scalarEqual(old, new reflect.Value) (equal bool, ok bool) {
+	switch old.Type() {
+	case timeType:
+		return old.Interface().(time.Time).Equal(new.Interface().(time.Time)), true
+	case timePtrType:
+		if old.IsNil() || new.IsNil() {
+			return old.IsNil() == new.IsNil(), true
+		}
+		return old.Interface().(*time.Time).Equal(*new.Interface().(*time.Time)), true
+	case localTimeType:
+		return old.Interface().(LocalTime).String() == new.Interface().(LocalTime).String(), true
+	}
+
+	switch old.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return old.Interface() == new.Interface(), true
+	}
+	return false, false
+}
+
+// jsonName returns the JSON name of a struct field
+func jsonName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// diffLists returns the values of new which are not in old, and the values of old which are not in new
+func diffLists(old, new []string) ListChange {
+	return ListChange{
+		Added:   subtractStrings(new, old),
+		Removed: subtractStrings(old, new),
+	}
+}
+
+func subtractStrings(values, others []string) []string {
+	set := make(map[string]bool, len(others))
+	for _, value := range others {
+		set[value] = true
+	}
+
+	var result []string
+	for _, value := range values {
+		if !set[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// diffCustomFields returns the changes of the custom fields, in the order of their keys
+func diffCustomFields(old, new map[string]string) []FieldChange {
+	keys := make([]string, 0, len(old)+len(new))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []FieldChange
+	for _, key := range keys {
+		oldValue, oldOK := old[key]
+		newValue, newOK := new[key]
+		if oldOK && newOK && oldValue == newValue {
+			continue
+		}
+
+		change := FieldChange{Field: key}
+		if oldOK {
+			change.Old = oldValue
+		}
+		if newOK {
+			change.New = newValue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// checkpointKey identifies a checkpoint across the snapshots of a tracking
+type checkpointKey struct {
+	time     string
+	message  string
+	location string
+}
+
+func keyOfCheckpoint(checkpoint Checkpoint) checkpointKey {
+	return checkpointKey{
+		time:     checkpoint.CheckpointTime.String(),
+		message:  checkpoint.Message,
+		location: checkpoint.Location,
+	}
+}
+
+// subtractCheckpoints returns the checkpoints which are not in others, counting the duplicated checkpoints
+func subtractCheckpoints(checkpoints, others []Checkpoint) []Checkpoint {
+	counts := make(map[checkpointKey]int, len(others))
+	for _, checkpoint := range others {
+		counts[keyOfCheckpoint(checkpoint)]++
+	}
+
+	var result []Checkpoint
+	for _, checkpoint := range checkpoints {
+		key := keyOfCheckpoint(checkpoint)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		result = append(result, checkpoint)
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package aftership
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTrackings(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	newUpdatedAt := updatedAt.Add(time.Hour)
+	pickedUp := Checkpoint{CheckpointTime: mustParseLocalTime("2024-03-01T10:00:00"), Message: "Picked up", Location: "Hong Kong"}
+	arrived := Checkpoint{CheckpointTime: mustParseLocalTime("2024-03-02T10:00:00"), Message: "Arrived", Location: "New York"}
+	delivered := Checkpoint{CheckpointTime: mustParseLocalTime("2024-03-03T10:00:00"), Message: "Delivered", Location: "New York"}
+
+	old := Tracking{
+		ID:               "1",
+		UpdatedAt:        &updatedAt,
+		Tag:              TagInTransit,
+		Subtag:           SubtagInTransit001,
+		TransitTime:      2,
+		Checkpoints:      []Checkpoint{pickedUp, arrived},
+		Emails:           []string{"a@example.com", "b@example.com"},
+		SMSes:            []string{"+85212345678"},
+		SubscribedEmails: []string{"a@example.com"},
+		CustomFields:     map[string]string{"a": "1", "b": "2"},
+		AdditionalField:  AdditionalField{DestinationState: "NY"},
+		LatestEstimatedDelivery: EstimatedDelivery{
+			Datetime: "2024-03-04",
+		},
+	}
+	new := old
+	new.UpdatedAt = &newUpdatedAt
+	new.Tag = TagDelivered
+	new.Subtag = SubtagDelivered001
+	new.ShipmentDeliveryDate = mustParseLocalTime("2024-03-03T10:00:00")
+	new.Checkpoints = []Checkpoint{pickedUp, delivered}
+	new.Emails = []string{"b@example.com", "c@example.com"}
+	new.SubscribedEmails = nil
+	new.SubscribedSMSes = []string{"+85212345678"}
+	new.CustomFields = map[string]string{"b": "3", "c": "4"}
+	new.AdditionalField = AdditionalField{DestinationState: "NJ"}
+	new.LatestEstimatedDelivery = EstimatedDelivery{Datetime: "2024-03-03"}
+
+	diff := DiffTrackings(old, new)
+	assert.False(t, diff.IsEmpty())
+
+	assert.Equal(t, []FieldChange{
+		{Field: "updated_at", Old: &updatedAt, New: &newUpdatedAt},
+		{Field: "shipment_delivery_date", Old: LocalTime{}, New: new.ShipmentDeliveryDate},
+		{Field: "tag", Old: TagInTransit, New: TagDelivered},
+		{Field: "subtag", Old: SubtagInTransit001, New: SubtagDelivered001},
+		{Field: "destination_state", Old: "NY", New: "NJ"},
+	}, diff.Fields)
+
+	change, ok := diff.Field("tag")
+	assert.True(t, ok)
+	assert.Equal(t, TagDelivered, change.New)
+	_, ok = diff.Field("transit_time")
+	assert.False(t, ok)
+
+	assert.Equal(t, []Checkpoint{delivered}, diff.AddedCheckpoints)
+	assert.Equal(t, []Checkpoint{arrived}, diff.RemovedCheckpoints)
+
+	assert.Equal(t, ListChange{Added: []string{"c@example.com"}, Removed: []string{"a@example.com"}}, diff.Emails)
+	assert.True(t, diff.SMSes.IsEmpty())
+	assert.Equal(t, ListChange{Removed: []string{"a@example.com"}}, diff.SubscribedEmails)
+	assert.Equal(t, ListChange{Added: []string{"+85212345678"}}, diff.SubscribedSMSes)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "a", Old: "1"},
+		{Field: "b", Old: "2", New: "3"},
+		{Field: "c", New: "4"},
+	}, diff.CustomFields)
+}
+
+func TestDiffTrackingsEqual(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	sameTime := updatedAt.In(time.FixedZone("HKT", 8*3600))
+	checkpoint := Checkpoint{CheckpointTime: mustParseLocalTime("2024-03-01T10:00:00"), Message: "Picked up"}
+
+	old := Tracking{
+		ID:           "1",
+		UpdatedAt:    &updatedAt,
+		Checkpoints:  []Checkpoint{checkpoint},
+		CustomFields: map[string]string{"a": "1"},
+	}
+	new := old
+	new.UpdatedAt = &sameTime
+	new.Checkpoints = []Checkpoint{checkpoint}
+	new.CustomFields = map[string]string{"a": "1"}
+
+	diff := DiffTrackings(old, new)
+	assert.True(t, diff.IsEmpty())
+	assert.True(t, DiffTrackings(Tracking{}, Tracking{}).IsEmpty())
+}
+
+func TestDiffTrackingsDuplicatedCheckpoints(t *testing.T) {
+	checkpoint := Checkpoint{CheckpointTime: mustParseLocalTime("2024-03-01T10:00:00"), Message: "Scanned"}
+
+	diff := DiffTrackings(
+		Tracking{Checkpoints: []Checkpoint{checkpoint}},
+		Tracking{Checkpoints: []Checkpoint{checkpoint, checkpoint}},
+	)
+	assert.Equal(t, []Checkpoint{checkpoint}, diff.AddedCheckpoints)
+	assert.Empty(t, diff.RemovedCheckpoints)
+}
+
+func TestDiffTrackingsNilTime(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	diff := DiffTrackings(Tracking{}, Tracking{CreatedAt: &createdAt})
+	assert.Len(t, diff.Fields, 1)
+	assert.Equal(t, "created_at", diff.Fields[0].Field)
+	assert.Nil(t, diff.Fields[0].Old.(*time.Time))
+}
@@ -2,6 +2,7 @@ package aftership
 
 import (
 	"encoding/json"
+	"net/http"
 )
 
 // Error messages
@@ -27,20 +28,116 @@ const (
 	codeRequestTimeout
 )
 
+// Errors of the documented meta codes returned by the AfterShip API, see
+// https://www.aftership.com/docs/tracking/quickstart/request-errors
+// An APIError matches them with errors.Is when it has the same code:
+//
+//	if errors.Is(err, aftership.ErrTrackingAlreadyExists) {
+//		// ...
+//	}
+var (
+	ErrBadRequest             = &APIError{Code: 400, Type: "BadRequest", Message: "The request was unacceptable, often due to missing a required parameter."}
+	ErrUnauthorized           = &APIError{Code: 401, Type: "Unauthorized", Message: "Invalid API key."}
+	ErrForbidden              = &APIError{Code: 403, Type: "Forbidden", Message: "The request is understood, but it has been refused or access is not allowed."}
+	ErrNotFound               = &APIError{Code: 404, Type: "NotFound", Message: "The URI requested is invalid or the resource requested does not exist."}
+	ErrTooManyRequests        = &APIError{Code: 429, Type: "TooManyRequests", Message: "You have exceeded the API call rate limit."}
+	ErrInternalError          = &APIError{Code: 500, Type: "InternalError", Message: "Something went wrong on AfterShip's end."}
+	ErrBadGateway             = &APIError{Code: 502, Type: "InternalError", Message: "Something went wrong on AfterShip's end."}
+	ErrServiceUnavailable     = &APIError{Code: 503, Type: "InternalError", Message: "Something went wrong on AfterShip's end."}
+	ErrGatewayTimeout         = &APIError{Code: 504, Type: "InternalError", Message: "Something went wrong on AfterShip's end."}
+	ErrInvalidJSON            = &APIError{Code: 4001, Type: "BadRequest", Message: "Invalid JSON data."}
+	ErrTrackingAlreadyExists  = &APIError{Code: 4003, Type: "BadRequest", Message: "Tracking already exists."}
+	ErrTrackingNotFound       = &APIError{Code: 4004, Type: "NotFound", Message: "Tracking does not exist."}
+	ErrInvalidTrackingNumber  = &APIError{Code: 4005, Type: "BadRequest", Message: "The value of tracking_number is invalid."}
+	ErrTrackingRequired       = &APIError{Code: 4006, Type: "BadRequest", Message: "tracking object is required."}
+	ErrTrackingNumberRequired = &APIError{Code: 4007, Type: "BadRequest", Message: "tracking_number is required."}
+	ErrInvalidField           = &APIError{Code: 4008, Type: "BadRequest", Message: "The value of a field is invalid."}
+	ErrFieldRequired          = &APIError{Code: 4009, Type: "BadRequest", Message: "A required field is missing."}
+	ErrInvalidSlug            = &APIError{Code: 4010, Type: "BadRequest", Message: "The value of slug is invalid."}
+	ErrMissingCourierFields   = &APIError{Code: 4011, Type: "BadRequest", Message: "Missing or invalid value of the required fields for this courier."}
+	ErrCourierNotDetected     = &APIError{Code: 4012, Type: "BadRequest", Message: "Cannot detect courier."}
+	ErrRetrackNotAllowed      = &APIError{Code: 4013, Type: "BadRequest", Message: "Retrack is not allowed. You can only retrack an inactive tracking."}
+	ErrNotificationRequired   = &APIError{Code: 4014, Type: "BadRequest", Message: "notification object is required."}
+	ErrInvalidID              = &APIError{Code: 4015, Type: "BadRequest", Message: "The value of id is invalid."}
+	ErrRetrackLimitReached    = &APIError{Code: 4016, Type: "BadRequest", Message: "Retrack is not allowed. You can only retrack each tracking once."}
+)
+
+// Errors of the system error codes generated by the SDK
+var (
+	ErrRateLimitExceeded = &APIError{Code: codeRateLimiting, Message: errExceedRateLimit}
+	ErrRequestFailed     = &APIError{Code: codeRequestFailed, Message: "HTTP request failed."}
+	ErrRequestTimeout    = &APIError{Code: codeRequestTimeout, Message: "HTTP request timeout."}
+)
+
 // APIError is the error in AfterShip API calls
 type APIError struct {
 	Code    int    `json:"code"`
 	Type    string `json:"type"`
 	Message string `json:"message"`
 	Path    string `json:"path"`
+
+	// StatusCode is the HTTP status code of the response, 0 if no response was received.
+	StatusCode int `json:"-"`
+
+	// RequestID is the value of the request-id header sent with the request.
+	RequestID string `json:"-"`
+
+	// EchoedRequestID is the request id echoed by the API in the response headers, empty if it didn't echo one.
+	// It may differ from RequestID when the API assigns its own id.
+	EchoedRequestID string `json:"-"`
+
+	// Body is the raw response body. It is only set when the response could not be decoded.
+	Body []byte `json:"-"`
+
+	// err is the underlying cause of the error
+	err error
 }
 
 // Error serializes the error object to JSON and returns it as a string.
+// The underlying cause, if any, is included in the "cause" field.
 func (e *APIError) Error() string {
-	ret, _ := json.Marshal(e)
+	if e.err == nil {
+		ret, _ := json.Marshal(e)
+		return string(ret)
+	}
+
+	ret, _ := json.Marshal(struct {
+		*APIError
+		Cause string `json:"cause"`
+	}{e, e.err.Error()})
 	return string(ret)
 }
 
+// Unwrap returns the underlying cause of the error, such as a transport or JSON decoding error.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is an APIError with the same code, so that errors.Is matches the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t != nil && t.Code == e.Code
+}
+
+// Retryable reports whether the request may succeed when it is sent again:
+// rate limiting, server errors, timeouts and transport failures are retryable.
+func (e *APIError) Retryable() bool {
+	switch e.Code {
+	case codeRateLimiting, codeRequestFailed, codeEmptyBody, codeRequestTimeout:
+		return true
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Temporary is the same as Retryable, following the convention of net.Error.
+func (e *APIError) Temporary() bool {
+	return e.Retryable()
+}
+
 // TooManyRequestsError is the too many requests error in AfterShip API calls
 type TooManyRequestsError struct {
 	APIError
@@ -52,3 +149,8 @@ func (e *TooManyRequestsError) Error() string {
 	ret, _ := json.Marshal(e)
 	return string(ret)
 }
+
+// Unwrap returns the embedded APIError, so that errors.As can match it.
+func (e *TooManyRequestsError) Unwrap() error {
+	return &e.APIError
+}
@@ -0,0 +1,77 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"meta": {
+				"code": 4003,
+				"type": "BadRequest",
+				"message": "Tracking already exists."
+			},
+			"data": {}
+		}`))
+	})
+
+	_, err := client.CreateTracking(context.Background(), CreateTrackingParams{
+		TrackingNumber: "1234567890",
+	})
+	assert.True(t, errors.Is(err, ErrTrackingAlreadyExists))
+	assert.False(t, errors.Is(err, ErrTrackingNotFound))
+	assert.False(t, errors.Is(err, ErrBadRequest))
+
+	// Too many requests
+	err = &TooManyRequestsError{APIError: APIError{Code: 429}}
+	assert.True(t, errors.Is(err, ErrTooManyRequests))
+
+	// Client side rate limit
+	err = &TooManyRequestsError{APIError: APIError{Code: codeRateLimiting}}
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+
+	assert.False(t, (&APIError{Code: 4003}).Is(errors.New("Tracking already exists.")))
+	assert.False(t, (&APIError{Code: 4003}).Is((*APIError)(nil)))
+}
+
+func TestAPIErrorRetryable(t *testing.T) {
+	tests := []struct {
+		err  *APIError
+		want bool
+	}{
+		{&APIError{Code: codeRateLimiting}, true},
+		{&APIError{Code: codeJSONError}, false},
+		{&APIError{Code: codeJSONError, StatusCode: http.StatusBadGateway}, true},
+		{&APIError{Code: codeBadRequest}, false},
+		{&APIError{Code: codeBadParam}, false},
+		{&APIError{Code: codeSignatureError}, false},
+		{&APIError{Code: codeRequestFailed}, true},
+		{&APIError{Code: codeEmptyBody}, true},
+		{&APIError{Code: codeRequestTimeout}, true},
+		{&APIError{Code: 429, StatusCode: http.StatusTooManyRequests}, true},
+		{&APIError{Code: 500, StatusCode: http.StatusInternalServerError}, true},
+		{&APIError{Code: 503}, true},
+		{&APIError{Code: 4003, StatusCode: http.StatusBadRequest}, false},
+		{&APIError{Code: 4004, StatusCode: http.StatusNotFound}, false},
+		{&APIError{Code: 401, StatusCode: http.StatusUnauthorized}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.err.Retryable(), tt.err.Code)
+		assert.Equal(t, tt.want, tt.err.Temporary(), tt.err.Code)
+	}
+
+	var temporary interface{ Temporary() bool }
+	err := error(&TooManyRequestsError{APIError: APIError{Code: 429, StatusCode: http.StatusTooManyRequests}})
+	assert.True(t, errors.As(err, &temporary))
+	assert.True(t, temporary.Temporary())
+}
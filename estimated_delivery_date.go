@@ -75,7 +75,7 @@ type EstimatedDeliveryDates struct {
 // BatchPredictEstimatedDeliveryDate Batch predict the estimated delivery dates
 func (client *Client) BatchPredictEstimatedDeliveryDate(ctx context.Context, params []EstimatedDeliveryDate) (EstimatedDeliveryDates, error) {
 	var dates EstimatedDeliveryDates
-	err := client.makeRequest(ctx, http.MethodPost, "/estimated-delivery-date/predict-batch", nil,
+	err := client.makeRequest(withOperation(ctx, "BatchPredictEstimatedDeliveryDate", ""), http.MethodPost, "/estimated-delivery-date/predict-batch", nil,
 		&batchPredictEstimatedDeliveryDateRequest{
 			EstimatedDeliveryDates: params,
 		}, &dates)
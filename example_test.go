@@ -1,7 +1,9 @@
 package aftership_test
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/aftership/aftership-sdk-go/v3"
 )
@@ -18,3 +20,32 @@ func ExampleNewClient() {
 
 	fmt.Println(cli)
 }
+
+func ExampleMiddleware() {
+	logging := func(next aftership.Handler) aftership.Handler {
+		return func(ctx context.Context, req *aftership.Request) (*aftership.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			fmt.Println(req.Operation, req.Method, req.Path, time.Since(start), err)
+			return resp, err
+		}
+	}
+
+	cli, err := aftership.NewClient(aftership.Config{
+		APIKey:      "YOUR_API_KEY",
+		Middlewares: []aftership.Middleware{logging},
+	})
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := cli.GetCouriers(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(result)
+}
@@ -0,0 +1,121 @@
+package aftership
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// localTimeLayout is a format of the times sent by the API
+type localTimeLayout struct {
+	layout    string
+	hasOffset bool
+	hasClock  bool
+}
+
+// localTimeLayouts are the formats of the times sent by the API, the carriers don't always send the zone offset or the time.
+var localTimeLayouts = []localTimeLayout{
+	{time.RFC3339, true, true},
+	{"2006-01-02T15:04:05", false, true},
+	{"2006-01-02 15:04:05", false, true},
+	{"2006-01-02T15:04Z07:00", true, true},
+	{"2006-01-02T15:04", false, true},
+	{"2006-01-02", false, false},
+}
+
+// LocalTime is a time sent by the API in the local time of the carrier, such as a checkpoint time.
+// The zone offset or the time of the day may be missing. The original text is kept,
+// and is sent back as is when the LocalTime is encoded to JSON.
+type LocalTime struct {
+	raw       string
+	time      time.Time
+	hasOffset bool
+	hasClock  bool
+}
+
+// ParseLocalTime parses a time sent by the API, in any of its formats, such as
+// "2018-07-31T10:33:00-04:00", "2018-07-31T10:33:00" or "2018-07-31".
+func ParseLocalTime(value string) (LocalTime, error) {
+	for _, layout := range localTimeLayouts {
+		if t, err := time.Parse(layout.layout, value); err == nil {
+			return LocalTime{
+				raw:       value,
+				time:      t,
+				hasOffset: layout.hasOffset,
+				hasClock:  layout.hasClock,
+			}, nil
+		}
+	}
+	return LocalTime{}, errors.Errorf("unknown time format %q", value)
+}
+
+// Time returns the time. If the zone offset is missing, the local time of the carrier is returned in UTC.
+// It returns the zero time if the time can't be parsed.
+func (t LocalTime) Time() time.Time {
+	return t.time
+}
+
+// Date returns the date in the local time of the carrier
+func (t LocalTime) Date() (year int, month time.Month, day int) {
+	return t.time.Date()
+}
+
+// HasOffset reports whether the zone offset was sent
+func (t LocalTime) HasOffset() bool {
+	return t.hasOffset
+}
+
+// HasClock reports whether the time of the day was sent, and not only the date
+func (t LocalTime) HasClock() bool {
+	return t.hasClock
+}
+
+// IsZero reports whether the time is missing or can't be parsed
+func (t LocalTime) IsZero() bool {
+	return t.time.IsZero()
+}
+
+// String returns the original text of the time
+func (t LocalTime) String() string {
+	return t.raw
+}
+
+// MarshalJSON encodes the original text of the time, or null if it is missing.
+// Tracking and Checkpoint leave out their missing times instead.
+func (t LocalTime) MarshalJSON() ([]byte, error) {
+	if t.raw == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.raw)
+}
+
+// omitEmptyLocalTime returns nil if the time is missing, so that omitempty leaves it out when it is encoded.
+// omitempty has no effect on a LocalTime, as it is a struct.
+func omitEmptyLocalTime(t LocalTime) *LocalTime {
+	if t.raw == "" {
+		return nil
+	}
+	return &t
+}
+
+// UnmarshalJSON decodes a time in any of the formats of the API.
+// A time in an unknown format is kept as text, and is zero.
+func (t *LocalTime) UnmarshalJSON(data []byte) error {
+	var raw *string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil || *raw == "" {
+		*t = LocalTime{}
+		return nil
+	}
+
+	parsed, err := ParseLocalTime(*raw)
+	if err != nil {
+		*t = LocalTime{raw: *raw}
+		return nil
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,116 @@
+package aftership
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseLocalTime(value string) LocalTime {
+	t, err := ParseLocalTime(value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseLocalTime(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      time.Time
+		hasOffset bool
+		hasClock  bool
+	}{
+		{"2018-07-31T10:33:00-04:00", time.Date(2018, 7, 31, 14, 33, 0, 0, time.UTC), true, true},
+		{"2018-07-31T10:33:00Z", time.Date(2018, 7, 31, 10, 33, 0, 0, time.UTC), true, true},
+		{"2018-07-31T10:33:00.123+08:00", time.Date(2018, 7, 31, 2, 33, 0, 123000000, time.UTC), true, true},
+		{"2018-07-31T10:33:00", time.Date(2018, 7, 31, 10, 33, 0, 0, time.UTC), false, true},
+		{"2018-07-31 10:33:00", time.Date(2018, 7, 31, 10, 33, 0, 0, time.UTC), false, true},
+		{"2018-07-31T10:33+09:00", time.Date(2018, 7, 31, 1, 33, 0, 0, time.UTC), true, true},
+		{"2018-07-31T10:33", time.Date(2018, 7, 31, 10, 33, 0, 0, time.UTC), false, true},
+		{"2018-07-31", time.Date(2018, 7, 31, 0, 0, 0, 0, time.UTC), false, false},
+	}
+	for _, cur := range tests {
+		tt := cur
+		t.Run(tt.value, func(t *testing.T) {
+			lt, err := ParseLocalTime(tt.value)
+			assert.Nil(t, err)
+			assert.True(t, tt.want.Equal(lt.Time()), lt.Time())
+			assert.Equal(t, tt.hasOffset, lt.HasOffset())
+			assert.Equal(t, tt.hasClock, lt.HasClock())
+			assert.Equal(t, tt.value, lt.String())
+			assert.False(t, lt.IsZero())
+
+			// The date is in the local time of the carrier
+			year, month, day := lt.Date()
+			assert.Equal(t, 2018, year)
+			assert.Equal(t, time.July, month)
+			assert.Equal(t, 31, day)
+		})
+	}
+
+	_, err := ParseLocalTime("31/07/2018")
+	assert.NotNil(t, err)
+}
+
+func TestLocalTimeJSON(t *testing.T) {
+	var checkpoint Checkpoint
+	err := json.Unmarshal([]byte(`{"checkpoint_time": "2018-07-31T10:33:00"}`), &checkpoint)
+	assert.Nil(t, err)
+	assert.Equal(t, mustParseLocalTime("2018-07-31T10:33:00"), checkpoint.CheckpointTime)
+
+	data, err := json.Marshal(checkpoint.CheckpointTime)
+	assert.Nil(t, err)
+	assert.Equal(t, `"2018-07-31T10:33:00"`, string(data))
+
+	// Missing times
+	for _, value := range []string{`null`, `""`} {
+		var lt LocalTime
+		assert.Nil(t, json.Unmarshal([]byte(value), &lt))
+		assert.True(t, lt.IsZero())
+		data, err = json.Marshal(lt)
+		assert.Nil(t, err)
+		assert.Equal(t, "null", string(data))
+	}
+
+	// Unknown formats are kept as text
+	var lt LocalTime
+	assert.Nil(t, json.Unmarshal([]byte(`"31/07/2018"`), &lt))
+	assert.True(t, lt.IsZero())
+	assert.Equal(t, "31/07/2018", lt.String())
+	data, err = json.Marshal(lt)
+	assert.Nil(t, err)
+	assert.Equal(t, `"31/07/2018"`, string(data))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`1532999580`), &lt))
+}
+
+func TestLocalTimeOmitEmpty(t *testing.T) {
+	// The missing times are left out, as they were when typed as string
+	data, err := json.Marshal(Checkpoint{Slug: "dhl"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"slug":"dhl"}`, string(data))
+
+	data, err = json.Marshal(Tracking{Slug: "dhl"})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "expected_delivery")
+	assert.NotContains(t, string(data), "shipment_pickup_date")
+	assert.NotContains(t, string(data), "shipment_delivery_date")
+	assert.NotContains(t, string(data), "first_attempted_at")
+
+	// The times are round-tripped
+	body := `{"slug":"dhl","expected_delivery":"2018-08-01","checkpoints":[{"checkpoint_time":"2018-07-31T10:33:00"}]}`
+	var tracking Tracking
+	assert.Nil(t, json.Unmarshal([]byte(body), &tracking))
+	data, err = json.Marshal(tracking)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"expected_delivery":"2018-08-01"`)
+	assert.Contains(t, string(data), `"checkpoints":[{"checkpoint_time":"2018-07-31T10:33:00"}]`)
+	assert.NotContains(t, string(data), "first_attempted_at")
+
+	var decoded Tracking
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, tracking, decoded)
+}
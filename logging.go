@@ -0,0 +1,114 @@
+package aftership
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Logger logs the requests sent by the client, *slog.Logger implements it.
+// Every request is logged at info level, its headers and bodies are logged at debug level.
+// The API key, the signatures and the personal data of the bodies are redacted.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+}
+
+const redacted = "REDACTED"
+
+// redactedFields are the JSON fields with personal data, redacted from the logged bodies
+var redactedFields = map[string]bool{
+	"emails":            true,
+	"smses":             true,
+	"customer_name":     true,
+	"subscribed_emails": true,
+	"subscribed_smses":  true,
+}
+
+// logRequest logs a request and its response. The response is nil when no response was received.
+func (client *Client) logRequest(req *http.Request, reqBody []byte,
+	resp *http.Response, respBody []byte, latency time.Duration, err error) {
+
+	logger := client.Config.Logger
+	if logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"method", req.Method,
+		"url", req.URL.String(),
+		"request_id", req.Header.Get("request-id"),
+		"latency", latency,
+	}
+	if resp != nil {
+		args = append(args,
+			"status", resp.StatusCode,
+			"x-ratelimit-limit", resp.Header.Get("x-ratelimit-limit"),
+			"x-ratelimit-remaining", resp.Header.Get("x-ratelimit-remaining"),
+			"x-ratelimit-reset", resp.Header.Get("x-ratelimit-reset"),
+		)
+	}
+	if err != nil {
+		args = append(args, "error", err.Error())
+	}
+	logger.Info("aftership request", args...)
+
+	args = []interface{}{
+		"request_id", req.Header.Get("request-id"),
+		"request_headers", redactHeader(req.Header),
+		"request_body", redactBody(reqBody),
+	}
+	if resp != nil {
+		args = append(args,
+			"response_headers", resp.Header,
+			"response_body", redactBody(respBody),
+		)
+	}
+	logger.Debug("aftership request details", args...)
+}
+
+// redactHeader returns a copy of the headers without the API key and the signatures.
+func redactHeader(header http.Header) http.Header {
+	ret := make(http.Header, len(header))
+	for key, values := range header {
+		lowerKey := strings.ToLower(key)
+		if lowerKey == "as-api-key" || strings.HasPrefix(lowerKey, "as-signature-") {
+			values = []string{redacted}
+		}
+		ret[key] = values
+	}
+	return ret
+}
+
+// redactBody returns the body with the personal data fields redacted. Bodies that are not JSON are returned as is.
+func redactBody(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return string(body)
+	}
+
+	ret, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return string(body)
+	}
+	return string(ret)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[key] && field != nil {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
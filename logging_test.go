@@ -0,0 +1,121 @@
+package aftership
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	entry := logEntry{level: level, msg: msg, args: make(map[string]interface{})}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) {
+	l.log("debug", msg, args)
+}
+
+func (l *testLogger) Info(msg string, args ...interface{}) {
+	l.log("info", msg, args)
+}
+
+func TestLogRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-reset", "1458463600")
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "9")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{
+			"meta": {"code": 201},
+			"data": {
+				"tracking": {
+					"tracking_number": "1234567890",
+					"emails": ["john@example.com"],
+					"subscribed_smses": ["+85291239123"],
+					"customer_name": "John"
+				}
+			}
+		}`))
+	})
+
+	logger := &testLogger{}
+	client.Config.Logger = logger
+	client.Config.AuthenticationType = AES
+	client.Config.APISecret = "YOUR_API_SECRET"
+
+	_, err := client.CreateTracking(context.Background(), CreateTrackingParams{
+		TrackingNumber: "1234567890",
+		Emails:         []string{"john@example.com"},
+		SMSes:          []string{"+85291239123"},
+		CustomerName:   "John",
+	})
+	assert.Nil(t, err)
+	assert.Len(t, logger.entries, 2)
+
+	info := logger.entries[0]
+	assert.Equal(t, "info", info.level)
+	assert.Equal(t, http.MethodPost, info.args["method"])
+	assert.Equal(t, server.URL+"/trackings", info.args["url"])
+	assert.NotEmpty(t, info.args["request_id"])
+	assert.Equal(t, http.StatusCreated, info.args["status"])
+	assert.Equal(t, "10", info.args["x-ratelimit-limit"])
+	assert.Equal(t, "9", info.args["x-ratelimit-remaining"])
+	assert.Equal(t, "1458463600", info.args["x-ratelimit-reset"])
+	assert.Contains(t, info.args, "latency")
+	assert.NotContains(t, info.args, "error")
+
+	debug := logger.entries[1]
+	assert.Equal(t, "debug", debug.level)
+	assert.Equal(t, info.args["request_id"], debug.args["request_id"])
+	headers := debug.args["request_headers"].(http.Header)
+	assert.Equal(t, redacted, headers.Get("as-api-key"))
+	assert.Equal(t, redacted, headers.Get("as-signature-hmac-sha256"))
+	assert.Equal(t,
+		`{"tracking":{"customer_name":"REDACTED","emails":"REDACTED","smses":"REDACTED","tracking_number":"1234567890"}}`,
+		debug.args["request_body"])
+	assert.Contains(t, debug.args["response_body"], `"customer_name":"REDACTED"`)
+	assert.Contains(t, debug.args["response_body"], `"subscribed_smses":"REDACTED"`)
+
+	for _, entry := range logger.entries {
+		logged := fmt.Sprint(entry.args)
+		for _, secret := range []string{"YOUR_API_KEY", "john@example.com", "+85291239123", "John"} {
+			assert.False(t, strings.Contains(logged, secret), secret)
+		}
+	}
+}
+
+func TestLogRequestFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &testLogger{}
+	client.Config.Logger = logger
+	client.Config.BaseURL = "http://127.0.0.1:0"
+
+	_, err := client.GetCouriers(context.Background())
+	assert.NotNil(t, err)
+	assert.Len(t, logger.entries, 2)
+	assert.NotEmpty(t, logger.entries[0].args["error"])
+	assert.NotContains(t, logger.entries[0].args, "status")
+	assert.NotContains(t, logger.entries[1].args, "response_body")
+}
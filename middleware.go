@@ -0,0 +1,67 @@
+package aftership
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request describes an API call made by the client, as seen by middlewares
+type Request struct {
+	// Operation is the name of the client method making the call, such as "CreateTracking".
+	Operation string
+
+	// Slug is the courier slug of the call, when it is known.
+	Slug string
+
+	// Method is the HTTP method of the call.
+	Method string
+
+	// Path is the URI path of the call, relative to the base URL.
+	Path string
+
+	// Query is the query parameters of the call, such as GetTrackingsParams. Nil if there is none.
+	Query interface{}
+
+	// Body is the request body before JSON encoding. Nil if there is none.
+	Body interface{}
+
+	// Header contains extra headers to send with the call. Headers added by a middleware are sent,
+	// and the "as-" prefixed ones are included in the request signature.
+	Header http.Header
+}
+
+// Handler makes an API call. The response is nil when no response could be decoded,
+// such as on transport failures.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to run code before and after every API call
+type Middleware func(next Handler) Handler
+
+// chain wraps the handler with the middlewares of the client. The first middleware is the outermost.
+func (client *Client) chain(handler Handler) Handler {
+	for i := len(client.Config.Middlewares) - 1; i >= 0; i-- {
+		handler = client.Config.Middlewares[i](handler)
+	}
+	return handler
+}
+
+// operation describes the client method making the call
+type operation struct {
+	name string
+	slug string
+}
+
+// operationKey is the context key of the operation
+type operationKey struct{}
+
+// withOperation returns a copy of ctx carrying the name of the client method making the call,
+// and the courier slug of the call if it is known.
+func withOperation(ctx context.Context, name string, slug string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation{name: name, slug: slug})
+}
+
+// operationFromContext returns the client method making the call.
+func operationFromContext(ctx context.Context) operation {
+	op, _ := ctx.Value(operationKey{}).(operation)
+	return op
+}
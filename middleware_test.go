@@ -0,0 +1,133 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewares(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings/dhl/1234567890", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "injected", r.Header.Get("as-custom-header"))
+		w.Header().Set("x-ratelimit-reset", "1458463600")
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "9")
+		w.Write([]byte(`{
+			"meta": {
+				"code": 200,
+				"message": "OK"
+			},
+			"data": {
+				"tracking": {
+					"id": "5b7658cec7c33c0e007de3c5",
+					"tracking_number": "1234567890",
+					"slug": "dhl"
+				}
+			}
+		}`))
+	})
+
+	var calls []string
+	client.Config.Middlewares = []Middleware{
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, "outer before")
+				assert.Equal(t, "GetTracking", req.Operation)
+				assert.Equal(t, "dhl", req.Slug)
+				assert.Equal(t, http.MethodGet, req.Method)
+				assert.Equal(t, "/trackings/dhl/1234567890", req.Path)
+				assert.Equal(t, GetTrackingParams{Fields: "title"}, req.Query)
+				assert.Nil(t, req.Body)
+				req.Header.Set("as-custom-header", "injected")
+
+				resp, err := next(ctx, req)
+				calls = append(calls, "outer after")
+				assert.Nil(t, err)
+				assert.Equal(t, 200, resp.Meta.Code)
+				assert.Equal(t, "OK", resp.Meta.Message)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "9", resp.Header.Get("x-ratelimit-remaining"))
+				assert.Equal(t, RateLimit{Reset: 1458463600, Limit: 10, Remaining: 9}, resp.RateLimit)
+				assert.Equal(t, "1234567890", resp.Data.(*trackingWrapper).Tracking.TrackingNumber)
+				return resp, err
+			}
+		},
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, "inner before")
+				resp, err := next(ctx, req)
+				calls = append(calls, "inner after")
+				return resp, err
+			}
+		},
+	}
+
+	tracking, err := client.GetTracking(context.Background(), SlugTrackingNumber{
+		Slug:           "dhl",
+		TrackingNumber: "1234567890",
+	}, GetTrackingParams{Fields: "title"})
+	assert.Nil(t, err)
+	assert.Equal(t, "5b7658cec7c33c0e007de3c5", tracking.ID)
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, calls)
+}
+
+func TestMiddlewareSeesErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"meta": {
+				"code": 4003,
+				"type": "BadRequest",
+				"message": "Tracking already exists."
+			},
+			"data": {}
+		}`))
+	})
+
+	var seen error
+	client.Config.Middlewares = []Middleware{
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				assert.Equal(t, "CreateTracking", req.Operation)
+				assert.Equal(t, &createTrackingRequest{Tracking: CreateTrackingParams{TrackingNumber: "1234567890"}}, req.Body)
+
+				resp, err := next(ctx, req)
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, 4003, resp.Meta.Code)
+				seen = err
+				return resp, err
+			}
+		},
+	}
+
+	_, err := client.CreateTracking(context.Background(), CreateTrackingParams{
+		TrackingNumber: "1234567890",
+	})
+	assert.Equal(t, seen, err)
+	assert.True(t, errors.Is(err, ErrTrackingAlreadyExists))
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.Middlewares = []Middleware{
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				return nil, ErrUnauthorized
+			}
+		},
+	}
+
+	_, err := client.GetCouriers(context.Background())
+	assert.Equal(t, ErrUnauthorized, err)
+}
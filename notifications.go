@@ -30,7 +30,7 @@ func (client *Client) GetNotification(ctx context.Context, identifier TrackingId
 
 	uriPath = fmt.Sprintf("/notifications%s", uriPath)
 	var wrapper notificationWrapper
-	err = client.makeRequest(ctx, http.MethodGet, uriPath, nil, nil, &wrapper)
+	err = client.makeRequest(withOperation(ctx, "GetNotification", slugOf(identifier)), http.MethodGet, uriPath, nil, nil, &wrapper)
 	return wrapper.Notification, err
 }
 
@@ -43,7 +43,7 @@ func (client *Client) AddNotification(ctx context.Context, identifier TrackingId
 
 	uriPath = fmt.Sprintf("/notifications%s/add", uriPath)
 	var wrapper notificationWrapper
-	err = client.makeRequest(ctx, http.MethodPost, uriPath, nil,
+	err = client.makeRequest(withOperation(ctx, "AddNotification", slugOf(identifier)), http.MethodPost, uriPath, nil,
 		&notificationWrapper{Notification: notification}, &wrapper)
 	return wrapper.Notification, err
 }
@@ -57,7 +57,7 @@ func (client *Client) RemoveNotification(ctx context.Context, identifier Trackin
 
 	uriPath = fmt.Sprintf("/notifications%s/remove", uriPath)
 	var wrapper notificationWrapper
-	err = client.makeRequest(ctx, http.MethodPost, uriPath, nil,
+	err = client.makeRequest(withOperation(ctx, "RemoveNotification", slugOf(identifier)), http.MethodPost, uriPath, nil,
 		&notificationWrapper{Notification: notification}, &wrapper)
 	return wrapper.Notification, err
 }
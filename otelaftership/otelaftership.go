@@ -0,0 +1,178 @@
+/*
+Package otelaftership instruments the AfterShip SDK client with OpenTelemetry.
+
+It creates a span for every API call, named after the client method, and records
+the latency and the errors of the calls.
+
+	client, err := aftership.NewClient(aftership.Config{
+		APIKey:      "YOUR_API_KEY",
+		Middlewares: []aftership.Middleware{otelaftership.Middleware()},
+	})
+*/
+package otelaftership
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the tracer and the meter
+const ScopeName = "github.com/aftership/aftership-sdk-go/v3/otelaftership"
+
+// Attribute keys of the spans and the metrics
+const (
+	OperationKey          = attribute.Key("aftership.operation")
+	SlugKey               = attribute.Key("aftership.slug")
+	PathKey               = attribute.Key("aftership.path")
+	MetaCodeKey           = attribute.Key("aftership.meta.code")
+	RateLimitRemainingKey = attribute.Key("aftership.rate_limit.remaining")
+)
+
+// Metric names
+const (
+	DurationMetric = "aftership.client.duration"
+	ErrorsMetric   = "aftership.client.errors"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures the middleware
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(cfg *config) {
+		cfg.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global meter provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(cfg *config) {
+		cfg.meterProvider = provider
+	}
+}
+
+// WithPropagators sets the propagators used to inject the trace context into the request headers.
+// Defaults to the global propagators.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(cfg *config) {
+		cfg.propagators = propagators
+	}
+}
+
+// Middleware returns a middleware creating a span per API call and recording the latency and the errors of the calls.
+func Middleware(opts ...Option) aftership.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(aftership.VERSION))
+	meter := cfg.meterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(aftership.VERSION))
+
+	duration, err := meter.Float64Histogram(DurationMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the AfterShip API calls."))
+	if err != nil {
+		otel.Handle(err)
+	}
+	errorCount, err := meter.Int64Counter(ErrorsMetric,
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of failed AfterShip API calls."))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next aftership.Handler) aftership.Handler {
+		return func(ctx context.Context, req *aftership.Request) (*aftership.Response, error) {
+			name := req.Operation
+			if name == "" {
+				name = "aftership " + req.Method
+			}
+
+			attrs := []attribute.KeyValue{
+				OperationKey.String(name),
+				semconv.HTTPRequestMethodKey.String(req.Method),
+			}
+			if req.Slug != "" {
+				attrs = append(attrs, SlugKey.String(req.Slug))
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+				trace.WithAttributes(PathKey.String(req.Path)))
+			defer span.End()
+
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			start := time.Now()
+			resp, err := next(ctx, req)
+			elapsed := time.Since(start)
+
+			statusCode, metaCode, rateLimit := result(resp, err)
+			if statusCode != 0 {
+				status := semconv.HTTPResponseStatusCodeKey.Int(statusCode)
+				attrs = append(attrs, status)
+				span.SetAttributes(status)
+			}
+			if metaCode != 0 {
+				span.SetAttributes(MetaCodeKey.Int(metaCode))
+			}
+			if rateLimit != nil && rateLimit.Limit > 0 {
+				span.SetAttributes(RateLimitRemainingKey.Int(rateLimit.Remaining))
+			}
+
+			duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				if metaCode != 0 {
+					attrs = append(attrs, MetaCodeKey.Int(metaCode))
+				}
+				errorCount.Add(ctx, 1, metric.WithAttributes(attrs...))
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// result returns the HTTP status code, the meta code and the rate limit of an API call, when they are known.
+func result(resp *aftership.Response, err error) (int, int, *aftership.RateLimit) {
+	if resp != nil {
+		return resp.StatusCode, resp.Meta.Code, &resp.RateLimit
+	}
+
+	var tooManyRequests *aftership.TooManyRequestsError
+	if errors.As(err, &tooManyRequests) {
+		return tooManyRequests.StatusCode, tooManyRequests.Code, tooManyRequests.RateLimit
+	}
+
+	var apiErr *aftership.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode, apiErr.Code, nil
+	}
+
+	return 0, 0, nil
+}
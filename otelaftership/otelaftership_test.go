@@ -0,0 +1,181 @@
+package otelaftership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testEnv struct {
+	spans  *tracetest.InMemoryExporter
+	reader *sdkmetric.ManualReader
+	client *aftership.Client
+	mux    *http.ServeMux
+}
+
+func setup(t *testing.T) *testEnv {
+	env := &testEnv{
+		spans:  tracetest.NewInMemoryExporter(),
+		reader: sdkmetric.NewManualReader(),
+		mux:    http.NewServeMux(),
+	}
+
+	server := httptest.NewServer(env.mux)
+	t.Cleanup(server.Close)
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(env.spans))
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(env.reader))
+
+	var err error
+	env.client, err = aftership.NewClient(aftership.Config{
+		APIKey:  "YOUR_API_KEY",
+		BaseURL: server.URL,
+		Middlewares: []aftership.Middleware{
+			Middleware(
+				WithTracerProvider(tracerProvider),
+				WithMeterProvider(meterProvider),
+				WithPropagators(propagation.TraceContext{}),
+			),
+		},
+	})
+	assert.Nil(t, err)
+
+	return env
+}
+
+func (env *testEnv) metrics(t *testing.T) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, env.reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, ScopeName, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	ret := make(map[attribute.Key]attribute.Value)
+	for _, kv := range kvs {
+		ret[kv.Key] = kv.Value
+	}
+	return ret
+}
+
+func TestSpanAndMetrics(t *testing.T) {
+	env := setup(t)
+
+	env.mux.HandleFunc("/last_checkpoint/dhl/1234567890", func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("traceparent"))
+		w.Header().Set("x-ratelimit-reset", "1458463600")
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "7")
+		w.Write([]byte(`{
+			"meta": {"code": 200},
+			"data": {"slug": "dhl", "tracking_number": "1234567890"}
+		}`))
+	})
+
+	_, err := env.client.GetLastCheckpoint(context.Background(), aftership.SlugTrackingNumber{
+		Slug:           "dhl",
+		TrackingNumber: "1234567890",
+	}, aftership.GetCheckpointParams{})
+	assert.Nil(t, err)
+
+	spans := env.spans.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GetLastCheckpoint", span.Name)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+
+	attrs := attributes(span.Attributes)
+	assert.Equal(t, "dhl", attrs[SlugKey].AsString())
+	assert.Equal(t, "/last_checkpoint/dhl/1234567890", attrs[PathKey].AsString())
+	assert.Equal(t, http.MethodGet, attrs[semconv.HTTPRequestMethodKey].AsString())
+	assert.Equal(t, int64(200), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+	assert.Equal(t, int64(200), attrs[MetaCodeKey].AsInt64())
+	assert.Equal(t, int64(7), attrs[RateLimitRemainingKey].AsInt64())
+
+	metrics := env.metrics(t)
+	histogram := metrics[DurationMetric].Data.(metricdata.Histogram[float64])
+	assert.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+	operation, _ := histogram.DataPoints[0].Attributes.Value(OperationKey)
+	assert.Equal(t, "GetLastCheckpoint", operation.AsString())
+	_, ok := metrics[ErrorsMetric]
+	assert.False(t, ok)
+}
+
+func TestErrorSpan(t *testing.T) {
+	env := setup(t)
+
+	env.mux.HandleFunc("/trackings/5b7658cec7c33c0e007de3c5", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{
+			"meta": {"code": 4004, "type": "NotFound", "message": "Tracking does not exist."},
+			"data": {}
+		}`))
+	})
+
+	_, err := env.client.GetTracking(context.Background(),
+		aftership.TrackingID("5b7658cec7c33c0e007de3c5"), aftership.GetTrackingParams{})
+	assert.True(t, errors.Is(err, aftership.ErrTrackingNotFound))
+
+	spans := env.spans.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GetTracking", span.Name)
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Len(t, span.Events, 1)
+
+	attrs := attributes(span.Attributes)
+	assert.Equal(t, int64(404), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+	assert.Equal(t, int64(4004), attrs[MetaCodeKey].AsInt64())
+	_, ok := attrs[SlugKey]
+	assert.False(t, ok)
+
+	metrics := env.metrics(t)
+	counter := metrics[ErrorsMetric].Data.(metricdata.Sum[int64])
+	assert.Len(t, counter.DataPoints, 1)
+	assert.Equal(t, int64(1), counter.DataPoints[0].Value)
+	metaCode, _ := counter.DataPoints[0].Attributes.Value(MetaCodeKey)
+	assert.Equal(t, int64(4004), metaCode.AsInt64())
+}
+
+func TestTransportErrorSpan(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+
+	handler := Middleware(WithTracerProvider(tracerProvider))(
+		func(ctx context.Context, req *aftership.Request) (*aftership.Response, error) {
+			assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+			return nil, aftership.ErrRequestFailed
+		})
+
+	_, err := handler(context.Background(), &aftership.Request{Method: http.MethodGet, Path: "/couriers", Header: http.Header{}})
+	assert.Equal(t, aftership.ErrRequestFailed, err)
+
+	span := spans.GetSpans()[0]
+	assert.Equal(t, "aftership GET", span.Name)
+	assert.Equal(t, codes.Error, span.Status.Code)
+	_, ok := attributes(span.Attributes)[semconv.HTTPResponseStatusCodeKey]
+	assert.False(t, ok)
+}
@@ -1,6 +1,11 @@
 package aftership
 
-import "time"
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
 
 // RateLimit is the X-RateLimit value in API response headers
 type RateLimit struct {
@@ -12,3 +17,122 @@ type RateLimit struct {
 func (rateLimit *RateLimit) isExceeded() bool {
 	return rateLimit.Remaining == 0 && rateLimit.Reset >= time.Now().Unix()
 }
+
+// resetAt returns the time when the rate limit is no longer exceeded.
+func (rateLimit *RateLimit) resetAt() time.Time {
+	return time.Unix(rateLimit.Reset+1, 0)
+}
+
+// RateLimitMode controls how the client behaves when the rate limit is exhausted
+type RateLimitMode int
+
+const (
+	// RateLimitDisabled sends every request regardless of the rate limit. This is the default mode.
+	RateLimitDisabled RateLimitMode = iota
+
+	// RateLimitBlock waits until the rate limit allows the request or the context is done.
+	RateLimitBlock
+
+	// RateLimitFailFast returns a TooManyRequestsError without sending the request.
+	RateLimitFailFast
+)
+
+// rateLimiter is a token bucket shared by all requests of a client.
+// The bucket size and refill rate come from the x-ratelimit-limit header, so no request is limited
+// until the first response is received. The bucket is emptied when the API reports no remaining requests.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rateLimit RateLimit
+	tokens    float64
+	refilled  time.Time
+}
+
+// reserve takes a token from the bucket. If none is available, it returns how long to wait for the next one.
+func (limiter *rateLimiter) reserve(now time.Time) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limit := float64(limiter.rateLimit.Limit)
+	if limit <= 0 {
+		return 0
+	}
+
+	if limiter.rateLimit.isExceeded() {
+		return limiter.rateLimit.resetAt().Sub(now)
+	}
+
+	limiter.tokens += now.Sub(limiter.refilled).Seconds() * limit
+	if limiter.tokens > limit {
+		limiter.tokens = limit
+	}
+	limiter.refilled = now
+
+	if limiter.tokens >= 1 {
+		limiter.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - limiter.tokens) / limit * float64(time.Second))
+}
+
+// wait blocks until a token is available. In fail fast mode, it returns an error instead of blocking.
+func (limiter *rateLimiter) wait(ctx context.Context, mode RateLimitMode, path string) error {
+	if mode == RateLimitDisabled {
+		return nil
+	}
+
+	for {
+		d := limiter.reserve(time.Now())
+		if d <= 0 {
+			return nil
+		}
+
+		var err error
+		if mode == RateLimitBlock {
+			err = sleepContext(ctx, d)
+		}
+		if mode == RateLimitFailFast || err != nil {
+			rateLimit := limiter.snapshot()
+			return &TooManyRequestsError{
+				APIError: APIError{
+					Code:    codeRateLimiting,
+					Message: errExceedRateLimit,
+					Path:    path,
+					err:     err,
+				},
+				RateLimit: &rateLimit,
+			}
+		}
+	}
+}
+
+// update synchronizes the bucket with the rate limit headers of the response.
+// It returns a snapshot of the rate limit that is not affected by later responses.
+func (limiter *rateLimiter) update(resp *http.Response) RateLimit {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	rateLimit := limiter.rateLimit
+	setRateLimit(&rateLimit, resp)
+
+	if rateLimit.Limit > 0 {
+		if limiter.rateLimit.Limit <= 0 {
+			limiter.tokens = float64(rateLimit.Limit)
+			limiter.refilled = time.Now()
+		}
+		if remaining := float64(rateLimit.Remaining); limiter.tokens > remaining {
+			limiter.tokens = remaining
+		}
+	}
+	limiter.rateLimit = rateLimit
+
+	return rateLimit
+}
+
+// snapshot returns the last rate limit reported by the API.
+func (limiter *rateLimiter) snapshot() RateLimit {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	return limiter.rateLimit
+}
@@ -0,0 +1,207 @@
+package aftership
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rateLimitResponse(rateLimit RateLimit) *http.Response {
+	header := http.Header{}
+	header.Set("x-ratelimit-reset", strconv.FormatInt(rateLimit.Reset, 10))
+	header.Set("x-ratelimit-limit", strconv.Itoa(rateLimit.Limit))
+	header.Set("x-ratelimit-remaining", strconv.Itoa(rateLimit.Remaining))
+	return &http.Response{Header: header}
+}
+
+func TestRateLimiterUnknownLimit(t *testing.T) {
+	limiter := &rateLimiter{}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve(time.Now()))
+	}
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	limiter := &rateLimiter{}
+	limiter.update(rateLimitResponse(RateLimit{
+		Reset:     time.Now().Unix(),
+		Limit:     10,
+		Remaining: 2,
+	}))
+
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+
+	// The bucket is empty, a token is refilled every 100ms
+	wait := limiter.reserve(now)
+	assert.True(t, wait > 0 && wait <= 100*time.Millisecond, wait)
+	assert.Equal(t, time.Duration(0), limiter.reserve(now.Add(100*time.Millisecond)))
+
+	// The bucket never holds more than the limit
+	later := now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve(later))
+	}
+	assert.True(t, limiter.reserve(later) > 0)
+}
+
+func TestRateLimiterExceeded(t *testing.T) {
+	limiter := &rateLimiter{}
+	reset := time.Now().Unix() + 2
+	limiter.update(rateLimitResponse(RateLimit{
+		Reset:     reset,
+		Limit:     10,
+		Remaining: 0,
+	}))
+
+	wait := limiter.reserve(time.Now())
+	assert.True(t, wait > time.Second, wait)
+	assert.Equal(t, RateLimit{Reset: reset, Limit: 10, Remaining: 0}, limiter.snapshot())
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := &rateLimiter{}
+	limiter.update(rateLimitResponse(RateLimit{
+		Reset:     time.Now().Unix() + 5,
+		Limit:     10,
+		Remaining: 0,
+	}))
+
+	// Fail fast
+	err := limiter.wait(context.Background(), RateLimitFailFast, "/test")
+	assert.NotNil(t, err)
+	tooManyRequests, ok := err.(*TooManyRequestsError)
+	assert.True(t, ok)
+	assert.Equal(t, codeRateLimiting, tooManyRequests.Code)
+	assert.Equal(t, "/test", tooManyRequests.Path)
+	assert.Equal(t, 0, tooManyRequests.RateLimit.Remaining)
+
+	// Block until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = limiter.wait(ctx, RateLimitBlock, "/test")
+	assert.NotNil(t, err)
+
+	// Disabled
+	assert.Nil(t, limiter.wait(context.Background(), RateLimitDisabled, "/test"))
+}
+
+func TestRateLimitBlockMode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RateLimitMode = RateLimitBlock
+
+	var calls int32
+	var reset int64
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		remaining := 2 - atomic.AddInt32(&calls, 1)
+		if remaining < 0 {
+			remaining = 0
+		}
+		w.Header().Set("x-ratelimit-reset", strconv.FormatInt(atomic.LoadInt64(&reset), 10))
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", strconv.Itoa(int(remaining)))
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	atomic.StoreInt64(&reset, time.Now().Unix())
+	var result string
+	for i := 0; i < 2; i++ {
+		err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+		assert.Nil(t, err)
+	}
+
+	// The rate limit is exhausted, the next request waits for the reset
+	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.Nil(t, err)
+	assert.True(t, time.Now().Unix() > atomic.LoadInt64(&reset))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRateLimitDisabledByDefault(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	reset := time.Now().Unix() + 5
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("x-ratelimit-reset", strconv.FormatInt(reset, 10))
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "0")
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	// The requests are sent even though the API reports no remaining requests
+	var result string
+	for i := 0; i < 3; i++ {
+		err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRateLimitSnapshot(t *testing.T) {
+	limiter := &rateLimiter{}
+	first := limiter.update(rateLimitResponse(RateLimit{Reset: 1, Limit: 10, Remaining: 9}))
+	second := limiter.update(rateLimitResponse(RateLimit{Reset: 2, Limit: 10, Remaining: 8}))
+
+	assert.Equal(t, RateLimit{Reset: 1, Limit: 10, Remaining: 9}, first)
+	assert.Equal(t, RateLimit{Reset: 2, Limit: 10, Remaining: 8}, second)
+	assert.Equal(t, second, limiter.snapshot())
+
+	// Missing headers keep the previous values
+	third := limiter.update(&http.Response{Header: http.Header{}})
+	assert.Equal(t, second, third)
+}
+
+func TestRateLimitConcurrentRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RateLimitMode = RateLimitBlock
+
+	var calls int32
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		remaining := 100 - atomic.AddInt32(&calls, 1)
+		w.Header().Set("x-ratelimit-reset", strconv.FormatInt(time.Now().Unix(), 10))
+		w.Header().Set("x-ratelimit-limit", "100")
+		w.Header().Set("x-ratelimit-remaining", strconv.Itoa(int(remaining)))
+		if remaining%3 == 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"meta": {"code": 429, "type": "TooManyRequests"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result string
+			err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+			if tooManyRequests, ok := err.(*TooManyRequestsError); ok {
+				// The snapshot attached to the error is not changed by other requests
+				remaining := tooManyRequests.RateLimit.Remaining
+				time.Sleep(10 * time.Millisecond)
+				assert.Equal(t, remaining, tooManyRequests.RateLimit.Remaining)
+				assert.Equal(t, 0, remaining%3)
+			}
+			client.GetRateLimit()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(20), atomic.LoadInt32(&calls))
+	assert.Equal(t, 100, client.GetRateLimit().Limit)
+}
@@ -16,140 +16,253 @@ import (
 	"github.com/google/uuid"
 )
 
-// makeRequest makes a AfterShip API calls
+// makeRequest makes a AfterShip API calls through the middlewares of the client
 func (client *Client) makeRequest(ctx context.Context, method string, path string,
 	queryParams interface{}, inputData interface{}, resultData interface{}) error {
 
+	op := operationFromContext(ctx)
+	request := &Request{
+		Operation: op.name,
+		Slug:      op.slug,
+		Method:    method,
+		Path:      path,
+		Query:     queryParams,
+		Body:      inputData,
+		Header:    headerFromContext(ctx),
+	}
+
+	handler := client.chain(func(ctx context.Context, request *Request) (*Response, error) {
+		return client.send(ctx, request, resultData)
+	})
+	resp, err := handler(ctx, request)
+	captureResponse(ctx, resp)
+	return err
+}
+
+// send sends the request, retrying failed attempts according to the retry policy
+func (client *Client) send(ctx context.Context, request *Request, resultData interface{}) (*Response, error) {
+	requestID := request.Header.Get("request-id")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
 	// Read input data
-	var body io.Reader
-	var bodyStr string
-	if inputData != nil {
-		jsonData, err := json.Marshal(inputData)
+	var bodyData []byte
+	if request.Body != nil {
+		jsonData, err := json.Marshal(request.Body)
 		if err != nil {
-			return &APIError{
-				Code:    codeJSONError,
-				Message: errMarshallingJSON,
+			return nil, &APIError{
+				Code:      codeJSONError,
+				Message:   errMarshallingJSON,
+				RequestID: requestID,
+				err:       err,
 			}
 		}
+		bodyData = jsonData
+	}
 
-		bodyStr = string(jsonData)
-		body = bytes.NewBuffer(jsonData)
+	var rawQuery string
+	if request.Query != nil {
+		queryStringObj, err := query.Values(request.Query)
+		if err != nil {
+			return nil, &APIError{
+				Code:      codeBadParam,
+				Message:   "Error when parsing query parameters.",
+				RequestID: requestID,
+				err:       err,
+			}
+		}
+		rawQuery = queryStringObj.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, client.Config.BaseURL+path, body)
+	policy := client.Config.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		// Retries wait for the rate limit even in fail fast mode
+		mode := client.Config.RateLimitMode
+		if attempt > 1 && mode == RateLimitFailFast {
+			mode = RateLimitBlock
+		}
+		if err := client.limiter.wait(ctx, mode, request.Path); err != nil {
+			return nil, err
+		}
+
+		result, err := client.doRequest(ctx, request, rawQuery, bodyData, requestID, resultData)
+		if err == nil || !policy.shouldRetry(attempt, err) {
+			return result, err
+		}
+
+		if sleepContext(ctx, policy.delay(attempt, err)) != nil {
+			return result, err
+		}
+	}
+}
+
+// doRequest sends a single request and decodes the response into resultData.
+// The returned response is nil when no response could be decoded.
+func (client *Client) doRequest(ctx context.Context, request *Request,
+	rawQuery string, bodyData []byte, requestID string, resultData interface{}) (*Response, error) {
+
+	var body io.Reader
+	var bodyStr string
+	if bodyData != nil {
+		bodyStr = string(bodyData)
+		body = bytes.NewReader(bodyData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.Method, client.Config.BaseURL+request.Path, body)
 	if err != nil {
-		return &APIError{
-			Code:    codeBadRequest,
-			Message: "Bad request.",
+		return nil, &APIError{
+			Code:      codeBadRequest,
+			Message:   "Bad request.",
+			RequestID: requestID,
+			err:       err,
 		}
 	}
+	req.URL.RawQuery = rawQuery
 
 	apiKey := client.Config.APIKey
 	// Add headers
 	contentType := "application/json"
 	req.Header.Add("Content-Type", contentType)
-	req.Header.Add("request-id", uuid.New().String())
+	req.Header.Add("request-id", requestID)
 	req.Header.Add("User-Agent", fmt.Sprintf("%s/%s", client.Config.UserAgentPrefix, VERSION))
 	req.Header.Add("aftership-agent", fmt.Sprintf("go-sdk-%s", VERSION))
 	req.Header.Add("as-api-key", apiKey)
-
-	if queryParams != nil {
-		queryStringObj, err := query.Values(queryParams)
-		if err != nil {
-			return &APIError{
-				Code:    codeBadParam,
-				Message: "Error when parsing query parameters.",
-			}
-		}
-		req.URL.RawQuery = queryStringObj.Encode()
+	for key, values := range request.Header {
+		req.Header[key] = values
 	}
 
-	authenticationType := client.Config.AuthenticationType
-
 	// set signature
-	if authenticationType == AES {
-		asHeaders := make(map[string]string)
-		for key, value := range req.Header {
-			asHeaders[key] = value[0]
+	if err := client.sign(req, contentType, bodyStr); err != nil {
+		return nil, &APIError{
+			Code:      codeSignatureError,
+			Message:   "Error when generating the request signature.",
+			RequestID: requestID,
+			err:       err,
 		}
-
-		date := time.Now().UTC().Format(http.TimeFormat)
-		signatureHeader, signature, err := GetSignature(
-			authenticationType, []byte(client.Config.APISecret), asHeaders,
-			contentType, req.URL.RequestURI(), req.Method, date, bodyStr)
-		if err != nil {
-			return &APIError{
-				Code:    codeSignatureError,
-				Message: "Error when generating the request signature.",
-			}
-		}
-
-		req.Header.Add("date", date)
-		req.Header.Add(signatureHeader, signature)
 	}
 
 	// Send request
+	start := time.Now()
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
+		client.logRequest(req, bodyData, nil, nil, time.Since(start), err)
 		if os.IsTimeout(err) {
-			return &APIError{
-				Code:    codeRequestTimeout,
-				Message: "HTTP request timeout.",
+			return nil, &APIError{
+				Code:      codeRequestTimeout,
+				Message:   "HTTP request timeout.",
+				RequestID: requestID,
+				err:       err,
 			}
 		}
-		return &APIError{
-			Code:    codeRequestFailed,
-			Message: "HTTP request failed.",
+		return nil, &APIError{
+			Code:      codeRequestFailed,
+			Message:   "HTTP request failed.",
+			RequestID: requestID,
+			err:       err,
 		}
 	}
 
 	defer resp.Body.Close()
 	contents, err := ioutil.ReadAll(resp.Body)
+	latency := time.Since(start)
+	client.logRequest(req, bodyData, resp, contents, latency, err)
+	echoedRequestID := responseRequestID(resp)
 	if err != nil {
-		return &APIError{
-			Code:    codeEmptyBody,
-			Message: "Unable to parse the API response.",
+		return nil, &APIError{
+			Code:            codeEmptyBody,
+			Message:         "Unable to parse the API response.",
+			StatusCode:      resp.StatusCode,
+			RequestID:       requestID,
+			EchoedRequestID: echoedRequestID,
+			err:             err,
 		}
 	}
 
 	// Rate Limit
-	setRateLimit(client.rateLimit, resp)
+	rateLimit := client.limiter.update(resp)
 
 	result := &Response{
-		Meta: Meta{},
-		Data: resultData,
+		Meta:            Meta{},
+		Data:            resultData,
+		StatusCode:      resp.StatusCode,
+		Header:          resp.Header,
+		RateLimit:       rateLimit,
+		RequestID:       requestID,
+		EchoedRequestID: echoedRequestID,
+		Latency:         latency,
 	}
 	// Unmarshal response object
 	err = json.Unmarshal(contents, result)
 	if err != nil {
-		return &APIError{
-			Code:    codeJSONError,
-			Message: "Invalid JSON data.",
+		return nil, &APIError{
+			Code:            codeJSONError,
+			Message:         "Invalid JSON data.",
+			StatusCode:      resp.StatusCode,
+			RequestID:       requestID,
+			EchoedRequestID: echoedRequestID,
+			Body:            contents,
+			err:             err,
 		}
 	}
 
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 		// The 2xx range indicate success
-		return nil
+		return result, nil
 	}
 
 	apiError := APIError{
-		Type:    result.Meta.Type,
-		Code:    result.Meta.Code,
-		Message: result.Meta.Message,
-		Path:    path,
+		Type:            result.Meta.Type,
+		Code:            result.Meta.Code,
+		Message:         result.Meta.Message,
+		Path:            request.Path,
+		StatusCode:      resp.StatusCode,
+		RequestID:       requestID,
+		EchoedRequestID: echoedRequestID,
 	}
 
 	// Too many requests error
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return &TooManyRequestsError{
+		return result, &TooManyRequestsError{
 			APIError:  apiError,
-			RateLimit: client.rateLimit,
+			RateLimit: &rateLimit,
 		}
 	}
 
 	// API error
-	return &apiError
+	return result, &apiError
+}
+
+// sign adds the date and the signature headers to req, if the requests are signed
+func (client *Client) sign(req *http.Request, contentType, body string) error {
+	signer := client.Config.Signer
+	if signer == nil {
+		var err error
+		signer, err = client.signers.get(client.Config.AuthenticationType, client.Config.APISecret)
+		if err != nil || signer == nil {
+			return err
+		}
+	}
+
+	asHeaders := make(map[string]string)
+	for key, value := range req.Header {
+		asHeaders[key] = value[0]
+	}
+
+	date := time.Now().UTC().Format(http.TimeFormat)
+	signString, err := getRequestSignString(asHeaders, contentType, req.URL.RequestURI(), req.Method, date, body)
+	if err != nil {
+		return err
+	}
+
+	signatureHeader, signature, err := signer.Sign(signString)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("date", date)
+	req.Header.Add(signatureHeader, signature)
+	return nil
 }
 
 func setRateLimit(rateLimit *RateLimit, resp *http.Response) {
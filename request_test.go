@@ -2,7 +2,16 @@ package aftership
 
 import (
 	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
 	"net/http"
 	"strconv"
 	"testing"
@@ -194,21 +203,27 @@ func TestRateLimit(t *testing.T) {
 			Message: "You have exceeded the API call rate limit. Default limit is 10 requests per second.",
 			Path:    "/test",
 		},
-		RateLimit: client.rateLimit,
+		RateLimit: &RateLimit{
+			Reset:     1458463600,
+			Limit:     10,
+			Remaining: 9,
+		},
 	}
 	exp, _ := json.Marshal(apiErr)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, string(exp), err.Error())
-	assert.Equal(t, int64(1458463600), client.rateLimit.Reset)
-	assert.Equal(t, 10, client.rateLimit.Limit)
-	assert.Equal(t, 9, client.rateLimit.Remaining)
+	assert.Equal(t, int64(1458463600), client.GetRateLimit().Reset)
+	assert.Equal(t, 10, client.GetRateLimit().Limit)
+	assert.Equal(t, 9, client.GetRateLimit().Remaining)
 }
 
 func TestBlockRequestWhenReachLimit(t *testing.T) {
 	setup()
 	defer teardown()
 
+	client.Config.RateLimitMode = RateLimitFailFast
+
 	reset := time.Now().Add(5000).Unix()
 	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodGet, r.Method)
@@ -228,14 +243,132 @@ func TestBlockRequestWhenReachLimit(t *testing.T) {
 
 	var result mockData
 	client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
-	assert.Equal(t, reset, client.rateLimit.Reset)
+	assert.Equal(t, reset, client.GetRateLimit().Reset)
 
 	// Another request after exceeded limits
-	exp, _ := json.Marshal(APIError{
-		Code:    codeRateLimiting,
-		Message: errExceedRateLimit,
+	exp, _ := json.Marshal(TooManyRequestsError{
+		APIError: APIError{
+			Code:    codeRateLimiting,
+			Message: errExceedRateLimit,
+			Path:    "/test",
+		},
+		RateLimit: &RateLimit{
+			Reset:     reset,
+			Limit:     10,
+			Remaining: 0,
+		},
 	})
 	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
 	assert.NotNil(t, err)
 	assert.Equal(t, string(exp), err.Error())
 }
+
+func TestMakeRequestErrorCause(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Timeout
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var result string
+	err := client.makeRequest(ctx, http.MethodGet, "/slow", nil, nil, &result)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeRequestTimeout, apiErr.Code)
+	assert.Equal(t, 0, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.Contains(t, err.Error(), `"cause":`)
+
+	// Invalid JSON
+	var requestID string
+	mux.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("request-id")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`<html>Bad Gateway</html>`))
+	})
+
+	err = client.makeRequest(context.Background(), http.MethodGet, "/html", nil, nil, &result)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeJSONError, apiErr.Code)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, requestID, apiErr.RequestID)
+	assert.Equal(t, []byte(`<html>Bad Gateway</html>`), apiErr.Body)
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+
+	// Too many requests
+	mux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("request-id")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"meta": {"code": 429, "type": "TooManyRequests"}}`))
+	})
+
+	err = client.makeRequest(context.Background(), http.MethodGet, "/limited", nil, nil, &result)
+	var tooManyRequests *TooManyRequestsError
+	assert.True(t, errors.As(err, &tooManyRequests))
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, requestID, apiErr.RequestID)
+
+	// Connection refused
+	teardown()
+	err = client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeRequestFailed, apiErr.Code)
+	var opErr *net.OpError
+	assert.True(t, errors.As(err, &opErr))
+}
+
+func TestRSASignedRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(HeaderAsSignatureHMAC))
+		signature, err := base64.StdEncoding.DecodeString(r.Header.Get(HeaderAsSignatureRSA))
+		assert.Nil(t, err)
+
+		signString, err := GetSignString(r.Method, "", "", r.Header.Get("date"),
+			"as-api-key:YOUR_API_KEY", "/test")
+		assert.Nil(t, err)
+		hashed := sha256.Sum256([]byte(signString))
+		assert.Nil(t, rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, hashed[:], signature,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}))
+
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {}}`))
+	})
+
+	client, err := NewClient(Config{
+		APIKey:             "YOUR_API_KEY",
+		AuthenticationType: RSA,
+		APISecret:          string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		BaseURL:            server.URL,
+	})
+	assert.Nil(t, err)
+
+	var result mockData
+	err = client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.Nil(t, err)
+
+	// The parsed key is reused until the secret changes
+	signer := client.signers.signer
+	err = client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.Nil(t, err)
+	assert.True(t, signer == client.signers.signer)
+
+	client.Config.APISecret = "not a PEM key"
+	err = client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeSignatureError, apiErr.Code)
+}
@@ -1,9 +1,32 @@
 package aftership
 
+import (
+	"net/http"
+	"time"
+)
+
 // Response is the message envelope for the AfterShip API response
 type Response struct {
 	Meta Meta        `json:"meta"`
 	Data interface{} `json:"data"`
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
+
+	// Header is the HTTP headers of the response
+	Header http.Header `json:"-"`
+
+	// RateLimit is the snapshot of the rate limit when the response was received
+	RateLimit RateLimit `json:"-"`
+
+	// RequestID is the value of the request-id header sent with the request
+	RequestID string `json:"-"`
+
+	// EchoedRequestID is the request id echoed by the API in the response headers, empty if it didn't echo one
+	EchoedRequestID string `json:"-"`
+
+	// Latency is the time between sending the request and reading the whole response
+	Latency time.Duration `json:"-"`
 }
 
 // Meta is used to communicate extra information about the response to the developer.
@@ -0,0 +1,126 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// defaultRetryableStatusCodes are the HTTP status codes retried when RetryPolicy.RetryableStatusCodes is empty
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how failed API calls are retried.
+// Transport failures and the configured HTTP status codes are retried with exponential backoff.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, it doubles on every subsequent retry. Defaults to 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts. Defaults to 10s.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of each delay that is randomized, between 0 and 1. Defaults to 0 (no jitter).
+	Jitter float64
+
+	// RetryableStatusCodes is the list of HTTP status codes to retry. Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatusCodes []int
+}
+
+// shouldRetry reports whether the failed attempt should be retried.
+func (policy RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	// No response was received
+	if apiErr.StatusCode == 0 {
+		return apiErr.Code == codeRequestFailed || apiErr.Code == codeRequestTimeout
+	}
+
+	codes := policy.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == apiErr.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the given retry attempt.
+// When the API reports a rate limit reset time, the delay lasts at least until the reset.
+func (policy RetryPolicy) delay(attempt int, err error) time.Duration {
+	base := policy.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	max := policy.MaxDelay
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(jitter * rand.Float64() * float64(d))
+	}
+
+	if tooManyRequests, ok := err.(*TooManyRequestsError); ok && tooManyRequests.RateLimit != nil {
+		if untilReset := time.Until(time.Unix(tooManyRequests.RateLimit.Reset, 0)); untilReset > d {
+			d = untilReset
+		}
+	}
+
+	return d
+}
+
+// sleepContext pauses for the given duration. It returns early with an error when ctx is done,
+// or immediately when the deadline of ctx would expire before the pause ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
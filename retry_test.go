@@ -0,0 +1,203 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	assert.True(t, policy.shouldRetry(1, &APIError{StatusCode: http.StatusInternalServerError}))
+	assert.True(t, policy.shouldRetry(2, &TooManyRequestsError{APIError: APIError{StatusCode: http.StatusTooManyRequests}}))
+	assert.False(t, policy.shouldRetry(3, &APIError{StatusCode: http.StatusInternalServerError}))
+	assert.False(t, policy.shouldRetry(1, &APIError{StatusCode: http.StatusBadRequest}))
+	assert.False(t, policy.shouldRetry(1, errors.New("not an API error")))
+
+	// Transport failures
+	assert.True(t, policy.shouldRetry(1, &APIError{Code: codeRequestFailed}))
+	assert.True(t, policy.shouldRetry(1, &APIError{Code: codeRequestTimeout}))
+	assert.False(t, policy.shouldRetry(1, &APIError{Code: codeBadRequest}))
+
+	// Client side rate limit
+	assert.False(t, policy.shouldRetry(1, &TooManyRequestsError{APIError: APIError{Code: codeRateLimiting}}))
+
+	// Custom status codes
+	policy.RetryableStatusCodes = []int{http.StatusConflict}
+	assert.True(t, policy.shouldRetry(1, &APIError{StatusCode: http.StatusConflict}))
+	assert.False(t, policy.shouldRetry(1, &APIError{StatusCode: http.StatusInternalServerError}))
+
+	// Retries disabled
+	assert.False(t, RetryPolicy{}.shouldRetry(1, &APIError{StatusCode: http.StatusInternalServerError}))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1, nil))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(2, nil))
+	assert.Equal(t, 400*time.Millisecond, policy.delay(3, nil))
+	assert.Equal(t, time.Second, policy.delay(5, nil))
+	assert.Equal(t, time.Second, policy.delay(100, nil))
+
+	// Defaults
+	assert.Equal(t, defaultRetryBaseDelay, RetryPolicy{}.delay(1, nil))
+	assert.Equal(t, defaultRetryMaxDelay, RetryPolicy{}.delay(100, nil))
+
+	// Jitter
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.delay(2, nil)
+		assert.True(t, d > 100*time.Millisecond && d <= 200*time.Millisecond, d)
+	}
+
+	// Wait until the rate limit reset
+	policy.Jitter = 0
+	err := &TooManyRequestsError{
+		RateLimit: &RateLimit{Reset: time.Now().Add(3 * time.Second).Unix()},
+	}
+	assert.True(t, policy.delay(1, err) > time.Second)
+}
+
+func TestMakeRequestRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RetryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	}
+
+	var calls int32
+	var requestIDs []string
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get("request-id"))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"meta": {"code": 503, "type": "ServiceUnavailable"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	var result string
+	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// The same request id is kept across attempts
+	assert.Equal(t, requestIDs[0], requestIDs[1])
+	assert.Equal(t, requestIDs[0], requestIDs[2])
+}
+
+func TestMakeRequestRetryExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RetryPolicy = RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+	}
+
+	var calls int32
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"meta": {"code": 500, "type": "InternalError"}}`))
+	})
+
+	var result string
+	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 500, err.(*APIError).Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestMakeRequestNoRetryOnClientError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RetryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	}
+
+	var calls int32
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"meta": {"code": 4004, "type": "NotFound"}}`))
+	})
+
+	var result string
+	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMakeRequestRetryTooManyRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RetryPolicy = RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+	}
+
+	var calls int32
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("x-ratelimit-reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.Header().Set("x-ratelimit-limit", "10")
+			w.Header().Set("x-ratelimit-remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"meta": {"code": 429, "type": "TooManyRequests"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": "test"}`))
+	})
+
+	var result string
+	err := client.makeRequest(context.Background(), http.MethodGet, "/test", nil, nil, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", result)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestMakeRequestRetryRespectsDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.RetryPolicy = RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Minute,
+	}
+
+	var calls int32
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"meta": {"code": 502, "type": "BadGateway"}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	var result string
+	err := client.makeRequest(ctx, http.MethodGet, "/test", nil, nil, &result)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.True(t, time.Since(start) < time.Second)
+}
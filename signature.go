@@ -3,9 +3,12 @@ package aftership
 import (
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
 	"errors"
 	"net/url"
 	"sort"
@@ -17,27 +20,38 @@ type AuthenticationType int64
 const (
 	APIKey AuthenticationType = iota
 	AES
+	RSA
 )
 
 const (
 	HeaderAsSignatureHMAC = "as-signature-hmac-sha256"
+	HeaderAsSignatureRSA  = "as-signature-rsa-sha256"
 )
 
 func GetSignature(authenticationType AuthenticationType, secretKey []byte, asHeaders map[string]string, contentType, uri, method, date, body string) (string, string, error) {
-	canonicalizedAmHeaders := GetCanonicalizedHeaders(asHeaders)
-	canonicalizedResource, err := GetCanonicalizedResource(uri)
+	signString, err := getRequestSignString(asHeaders, contentType, uri, method, date, body)
 	if err != nil {
 		return "", "", err
 	}
-	signString, err := GetSignString(method, body, contentType, date, canonicalizedAmHeaders, canonicalizedResource)
+
+	signer, err := newSigner(authenticationType, secretKey)
 	if err != nil {
 		return "", "", err
 	}
+	if signer == nil {
+		return "", "", errors.New("authenticationType incorrect")
+	}
+	return signer.Sign(signString)
+}
 
-	if authenticationType == AES {
-		return HeaderAsSignatureHMAC, GetHMACSignature(signString, secretKey), nil
+// getRequestSignString returns the canonical string of a request to sign
+func getRequestSignString(asHeaders map[string]string, contentType, uri, method, date, body string) (string, error) {
+	canonicalizedAmHeaders := GetCanonicalizedHeaders(asHeaders)
+	canonicalizedResource, err := GetCanonicalizedResource(uri)
+	if err != nil {
+		return "", err
 	}
-	return "", "", errors.New("authenticationType incorrect")
+	return GetSignString(method, body, contentType, date, canonicalizedAmHeaders, canonicalizedResource)
 }
 
 func GetHMACSignature(signString string, secret []byte) string {
@@ -47,6 +61,40 @@ func GetHMACSignature(signString string, secret []byte) string {
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
+// GetRSASignature signs signString with RSASSA-PSS and SHA256, the salt length being the hash length.
+// privateKey is a PEM encoded PKCS#1 or PKCS#8 RSA private key.
+func GetRSASignature(signString string, privateKey []byte) (string, error) {
+	key, err := ParseRSAPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	_, signature, err := NewRSASigner(key).Sign(signString)
+	return signature, err
+}
+
+// ParseRSAPrivateKey parses a PEM encoded PKCS#1 or PKCS#8 RSA private key
+func ParseRSAPrivateKey(privateKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("invalid RSA private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("invalid RSA private key: not a PKCS#1 or PKCS#8 key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid RSA private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
+
 func GetSignString(method, body, contentType, date, canonicalizedAmHeaders, canonicalizedResource string) (signature string, err error) {
 	result := ""
 
@@ -0,0 +1,101 @@
+package aftership
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sync"
+)
+
+// Signer signs the requests sent by the client, so that the secret doesn't have to be set in the config.
+// signString is the canonical string of the request, as built by GetSignString.
+// Sign returns the name of the signature header and the signature.
+type Signer interface {
+	Sign(signString string) (header string, signature string, err error)
+}
+
+type hmacSigner struct {
+	secret []byte
+}
+
+// NewHMACSigner returns a Signer signing with HMAC-SHA256, as the AES authentication type.
+func NewHMACSigner(secret []byte) Signer {
+	return &hmacSigner{secret: secret}
+}
+
+func (s *hmacSigner) Sign(signString string) (string, string, error) {
+	return HeaderAsSignatureHMAC, GetHMACSignature(signString, s.secret), nil
+}
+
+type rsaSigner struct {
+	key crypto.Signer
+}
+
+// NewRSASigner returns a Signer signing with RSASSA-PSS and SHA256, as the RSA authentication type.
+// key holds an RSA private key, such as an *rsa.PrivateKey or a key stored in a KMS or an HSM.
+func NewRSASigner(key crypto.Signer) Signer {
+	return &rsaSigner{key: key}
+}
+
+func (s *rsaSigner) Sign(signString string) (string, string, error) {
+	if _, ok := s.key.Public().(*rsa.PublicKey); !ok {
+		return "", "", errors.New("invalid RSA signer: not an RSA key")
+	}
+
+	hashed := sha256.Sum256([]byte(signString))
+	signature, err := s.key.Sign(rand.Reader, hashed[:], &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return HeaderAsSignatureRSA, base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// newSigner returns the Signer of an authentication type, nil for the APIKey authentication type.
+func newSigner(authenticationType AuthenticationType, secretKey []byte) (Signer, error) {
+	switch authenticationType {
+	case APIKey:
+		return nil, nil
+	case AES:
+		return NewHMACSigner(secretKey), nil
+	case RSA:
+		key, err := ParseRSAPrivateKey(secretKey)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSASigner(key), nil
+	}
+	return nil, errors.New("authenticationType incorrect")
+}
+
+// signerCache keeps the signer of the last authentication type and secret of the config,
+// so that the RSA key is not parsed again on every request.
+type signerCache struct {
+	mu                 sync.Mutex
+	authenticationType AuthenticationType
+	secret             string
+	signer             Signer
+}
+
+// get returns the signer of an authentication type and a secret, nil for the APIKey authentication type.
+func (c *signerCache) get(authenticationType AuthenticationType, secret string) (Signer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.signer != nil && c.authenticationType == authenticationType && c.secret == secret {
+		return c.signer, nil
+	}
+
+	signer, err := newSigner(authenticationType, []byte(secret))
+	if err != nil || signer == nil {
+		return nil, err
+	}
+	c.authenticationType, c.secret, c.signer = authenticationType, secret, signer
+	return signer, nil
+}
@@ -0,0 +1,93 @@
+package aftership
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signerFunc func(signString string) (string, string, error)
+
+func (f signerFunc) Sign(signString string) (string, string, error) {
+	return f(signString)
+}
+
+func TestHMACSigner(t *testing.T) {
+	header, signature, err := NewHMACSigner([]byte("YOUR_API_SECRET")).Sign("GET\n\n\n\n\n/trackings")
+	assert.Nil(t, err)
+	assert.Equal(t, HeaderAsSignatureHMAC, header)
+	assert.Equal(t, GetHMACSignature("GET\n\n\n\n\n/trackings", []byte("YOUR_API_SECRET")), signature)
+}
+
+func TestRSASigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	header, signature, err := NewRSASigner(key).Sign("GET\n\n\n\n\n/trackings")
+	assert.Nil(t, err)
+	assert.Equal(t, HeaderAsSignatureRSA, header)
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	assert.Nil(t, err)
+	hashed := sha256.Sum256([]byte("GET\n\n\n\n\n/trackings"))
+	assert.Nil(t, rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, hashed[:], decoded,
+		&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}))
+
+	// Not an RSA key
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	_, _, err = NewRSASigner(ecKey).Sign("GET\n\n\n\n\n/trackings")
+	assert.NotNil(t, err)
+}
+
+func TestConfigSigner(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/couriers", func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("date"))
+		assert.Equal(t, "signed", r.Header.Get("as-signature-kms"))
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"couriers": []}}`))
+	})
+
+	var signed string
+	client, err := NewClient(Config{
+		APIKey:             "YOUR_API_KEY",
+		AuthenticationType: RSA,
+		BaseURL:            server.URL,
+		Signer: signerFunc(func(signString string) (string, string, error) {
+			signed = signString
+			return "as-signature-kms", "signed", nil
+		}),
+	})
+	assert.Nil(t, err)
+
+	_, err = client.GetCouriers(context.Background())
+	assert.Nil(t, err)
+	assert.Contains(t, signed, "as-api-key:YOUR_API_KEY\n/couriers")
+}
+
+func TestConfigSignerError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Config.Signer = signerFunc(func(signString string) (string, string, error) {
+		return "", "", errors.New("key not found")
+	})
+
+	_, err := client.GetCouriers(context.Background())
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeSignatureError, apiErr.Code)
+	assert.EqualError(t, errors.Unwrap(err), "key not found")
+}
@@ -0,0 +1,185 @@
+package aftership
+
+import "strings"
+
+// Tag is the delivery status of a tracking or a checkpoint.
+// Statuses added to the API after this SDK version are decoded as is, and are not known.
+type Tag string
+
+// Delivery statuses
+const (
+	TagPending            Tag = "Pending"
+	TagInfoReceived       Tag = "InfoReceived"
+	TagInTransit          Tag = "InTransit"
+	TagOutForDelivery     Tag = "OutForDelivery"
+	TagAttemptFail        Tag = "AttemptFail"
+	TagDelivered          Tag = "Delivered"
+	TagAvailableForPickup Tag = "AvailableForPickup"
+	TagException          Tag = "Exception"
+	TagExpired            Tag = "Expired"
+)
+
+// tagDescriptions are the descriptions of the delivery statuses documented by the API
+var tagDescriptions = map[Tag]string{
+	TagPending:            "New shipment added that is pending to track, or has no tracking information yet",
+	TagInfoReceived:       "Carrier has received the request from the shipper and is about to pick up the shipment",
+	TagInTransit:          "Carrier has accepted or picked up the shipment from the shipper, and the shipment is on the way",
+	TagOutForDelivery:     "Carrier is about to deliver the shipment, or it is ready to pick up",
+	TagAttemptFail:        "Carrier attempted to deliver but failed, and usually leaves a notice and will try to deliver again",
+	TagDelivered:          "The shipment was delivered successfully",
+	TagAvailableForPickup: "The package arrived at a pickup point near the recipient and is available for pickup",
+	TagException:          "Custom hold, undelivered, returned shipment to sender or any shipping exceptions",
+	TagExpired:            "Shipment has no tracking information for 30 days since added",
+}
+
+// IsKnown reports whether the tag is a delivery status known by this SDK version
+func (tag Tag) IsKnown() bool {
+	_, ok := tagDescriptions[tag]
+	return ok
+}
+
+// IsTerminal reports whether no more updates are expected for the tag, Delivered and Expired.
+func (tag Tag) IsTerminal() bool {
+	return tag == TagDelivered || tag == TagExpired
+}
+
+// IsProblem reports whether the tag needs attention, AttemptFail, Exception and Expired.
+func (tag Tag) IsProblem() bool {
+	return tag == TagAttemptFail || tag == TagException || tag == TagExpired
+}
+
+// Description returns a human readable description of the tag, or the tag itself if it is not known.
+func (tag Tag) Description() string {
+	if description, ok := tagDescriptions[tag]; ok {
+		return description
+	}
+	return string(tag)
+}
+
+// Subtag is the detailed delivery status of a tracking or a checkpoint, such as "Delivered_001".
+// Statuses added to the API after this SDK version are decoded as is, and are not known,
+// their description falls back to the one of their delivery status.
+type Subtag string
+
+// Detailed delivery statuses
+const (
+	SubtagPending001            Subtag = "Pending_001"
+	SubtagPending002            Subtag = "Pending_002"
+	SubtagPending003            Subtag = "Pending_003"
+	SubtagPending004            Subtag = "Pending_004"
+	SubtagPending005            Subtag = "Pending_005"
+	SubtagPending006            Subtag = "Pending_006"
+	SubtagInfoReceived001       Subtag = "InfoReceived_001"
+	SubtagInTransit001          Subtag = "InTransit_001"
+	SubtagInTransit002          Subtag = "InTransit_002"
+	SubtagInTransit003          Subtag = "InTransit_003"
+	SubtagInTransit004          Subtag = "InTransit_004"
+	SubtagInTransit005          Subtag = "InTransit_005"
+	SubtagInTransit006          Subtag = "InTransit_006"
+	SubtagInTransit007          Subtag = "InTransit_007"
+	SubtagInTransit008          Subtag = "InTransit_008"
+	SubtagInTransit009          Subtag = "InTransit_009"
+	SubtagOutForDelivery001     Subtag = "OutForDelivery_001"
+	SubtagOutForDelivery003     Subtag = "OutForDelivery_003"
+	SubtagOutForDelivery004     Subtag = "OutForDelivery_004"
+	SubtagAttemptFail001        Subtag = "AttemptFail_001"
+	SubtagAttemptFail002        Subtag = "AttemptFail_002"
+	SubtagAttemptFail003        Subtag = "AttemptFail_003"
+	SubtagDelivered001          Subtag = "Delivered_001"
+	SubtagDelivered002          Subtag = "Delivered_002"
+	SubtagDelivered003          Subtag = "Delivered_003"
+	SubtagDelivered004          Subtag = "Delivered_004"
+	SubtagAvailableForPickup001 Subtag = "AvailableForPickup_001"
+	SubtagException001          Subtag = "Exception_001"
+	SubtagException002          Subtag = "Exception_002"
+	SubtagException003          Subtag = "Exception_003"
+	SubtagException004          Subtag = "Exception_004"
+	SubtagException005          Subtag = "Exception_005"
+	SubtagException006          Subtag = "Exception_006"
+	SubtagException007          Subtag = "Exception_007"
+	SubtagException008          Subtag = "Exception_008"
+	SubtagException009          Subtag = "Exception_009"
+	SubtagException010          Subtag = "Exception_010"
+	SubtagException011          Subtag = "Exception_011"
+	SubtagException012          Subtag = "Exception_012"
+	SubtagException013          Subtag = "Exception_013"
+	SubtagExpired001            Subtag = "Expired_001"
+)
+
+// subtagDescriptions are the descriptions of the detailed delivery statuses documented by the API
+var subtagDescriptions = map[Subtag]string{
+	SubtagPending001:            "No information yet on the shipment",
+	SubtagPending002:            "The carrier has not received the shipment yet",
+	SubtagPending003:            "The tracking information is being retrieved from the carrier",
+	SubtagPending004:            "The tracking number is not found in the system of the carrier yet",
+	SubtagPending005:            "The tracking number may be invalid",
+	SubtagPending006:            "The carrier of the tracking number may be incorrect",
+	SubtagInfoReceived001:       "The carrier received the request from the shipper and is about to pick up the shipment",
+	SubtagInTransit001:          "Shipment on the way",
+	SubtagInTransit002:          "Shipment accepted by the carrier",
+	SubtagInTransit003:          "Shipment arrived at a hub or sorting center",
+	SubtagInTransit004:          "Shipment arrived at the destination country or region",
+	SubtagInTransit005:          "Customs clearance completed",
+	SubtagInTransit006:          "Customs clearance started",
+	SubtagInTransit007:          "Shipment departed from a facility",
+	SubtagInTransit008:          "Problem resolved and shipment in transit",
+	SubtagInTransit009:          "Shipment forwarded to a different delivery address",
+	SubtagOutForDelivery001:     "The package is out for delivery",
+	SubtagOutForDelivery003:     "The recipient is contacted before the final delivery",
+	SubtagOutForDelivery004:     "A delivery appointment is scheduled",
+	SubtagAttemptFail001:        "The delivery attempt failed",
+	SubtagAttemptFail002:        "The recipient is not available at the given address",
+	SubtagAttemptFail003:        "The business is closed at the time of delivery",
+	SubtagDelivered001:          "Shipment delivered successfully",
+	SubtagDelivered002:          "Package picked up by the recipient",
+	SubtagDelivered003:          "Package delivered to and signed by the recipient",
+	SubtagDelivered004:          "Package delivered and cash collected on delivery",
+	SubtagAvailableForPickup001: "The package arrived at a pickup point and is available for pickup",
+	SubtagException001:          "Delivery of the package failed due to a shipping exception",
+	SubtagException002:          "The recipient moved",
+	SubtagException003:          "The recipient refused the package",
+	SubtagException004:          "The package was unclaimed",
+	SubtagException005:          "Delivery of the package failed due to unforeseen reasons",
+	SubtagException006:          "The package is held by customs",
+	SubtagException007:          "The package is delayed due to issues during transit",
+	SubtagException008:          "Delivery of the package failed due to an incorrect address",
+	SubtagException009:          "The shipment was cancelled",
+	SubtagException010:          "The package is returning to the sender",
+	SubtagException011:          "The package was returned to the sender",
+	SubtagException012:          "The package was damaged",
+	SubtagException013:          "The package was lost",
+	SubtagExpired001:            "No tracking update for 30 days",
+}
+
+// Tag returns the delivery status of the subtag, such as Delivered for "Delivered_001"
+func (subtag Subtag) Tag() Tag {
+	if i := strings.LastIndex(string(subtag), "_"); i >= 0 {
+		return Tag(subtag[:i])
+	}
+	return Tag(subtag)
+}
+
+// IsKnown reports whether the subtag is a detailed delivery status known by this SDK version
+func (subtag Subtag) IsKnown() bool {
+	_, ok := subtagDescriptions[subtag]
+	return ok
+}
+
+// IsTerminal reports whether no more updates are expected for the delivery status of the subtag
+func (subtag Subtag) IsTerminal() bool {
+	return subtag.Tag().IsTerminal()
+}
+
+// IsProblem reports whether the delivery status of the subtag needs attention
+func (subtag Subtag) IsProblem() bool {
+	return subtag.Tag().IsProblem()
+}
+
+// Description returns a human readable description of the subtag, or the description of its delivery status
+// if it is not known.
+func (subtag Subtag) Description() string {
+	if description, ok := subtagDescriptions[subtag]; ok {
+		return description
+	}
+	return subtag.Tag().Description()
+}
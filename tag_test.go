@@ -0,0 +1,81 @@
+package aftership
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		tag      Tag
+		known    bool
+		terminal bool
+		problem  bool
+	}{
+		{TagPending, true, false, false},
+		{TagInfoReceived, true, false, false},
+		{TagInTransit, true, false, false},
+		{TagOutForDelivery, true, false, false},
+		{TagAttemptFail, true, false, true},
+		{TagDelivered, true, true, false},
+		{TagAvailableForPickup, true, false, false},
+		{TagException, true, false, true},
+		{TagExpired, true, true, true},
+		{"Teleported", false, false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.known, tt.tag.IsKnown(), tt.tag)
+		assert.Equal(t, tt.terminal, tt.tag.IsTerminal(), tt.tag)
+		assert.Equal(t, tt.problem, tt.tag.IsProblem(), tt.tag)
+		assert.NotEmpty(t, tt.tag.Description(), tt.tag)
+	}
+	assert.Equal(t, "Teleported", Tag("Teleported").Description())
+}
+
+func TestSubtag(t *testing.T) {
+	for subtag := range subtagDescriptions {
+		assert.True(t, subtag.Tag().IsKnown(), subtag)
+	}
+
+	assert.Equal(t, TagDelivered, SubtagDelivered003.Tag())
+	assert.Equal(t, TagAvailableForPickup, SubtagAvailableForPickup001.Tag())
+	assert.True(t, SubtagDelivered003.IsKnown())
+	assert.True(t, SubtagDelivered003.IsTerminal())
+	assert.False(t, SubtagDelivered003.IsProblem())
+	assert.True(t, SubtagException002.IsProblem())
+	assert.Equal(t, "Package delivered to and signed by the recipient", SubtagDelivered003.Description())
+
+	// Subtags used by the return_to_sender field and the lost shipments
+	assert.True(t, Subtag("InTransit_006").IsKnown())
+	assert.Equal(t, "Customs clearance started", SubtagInTransit006.Description())
+	assert.True(t, Subtag("Exception_011").IsKnown())
+	assert.Equal(t, "The package was returned to the sender", SubtagException011.Description())
+	assert.True(t, Subtag("Exception_013").IsProblem())
+	assert.Equal(t, "The package was lost", SubtagException013.Description())
+	assert.True(t, Subtag("Pending_006").IsKnown())
+	assert.Equal(t, TagPending, SubtagPending006.Tag())
+
+	// Subtags added to the API later
+	unknown := Subtag("Exception_099")
+	assert.False(t, unknown.IsKnown())
+	assert.Equal(t, TagException, unknown.Tag())
+	assert.True(t, unknown.IsProblem())
+	assert.Equal(t, TagException.Description(), unknown.Description())
+	assert.Equal(t, Tag("Unknown"), Subtag("Unknown").Tag())
+}
+
+func TestDecodeTags(t *testing.T) {
+	var tracking Tracking
+	err := json.Unmarshal([]byte(`{
+		"tag": "Delivered",
+		"subtag": "Delivered_001",
+		"checkpoints": [{"tag": "Teleported", "subtag": "Teleported_001"}]
+	}`), &tracking)
+	assert.Nil(t, err)
+	assert.Equal(t, TagDelivered, tracking.Tag)
+	assert.Equal(t, SubtagDelivered001, tracking.Subtag)
+	assert.Equal(t, Tag("Teleported"), tracking.Checkpoints[0].Tag)
+	assert.False(t, tracking.Checkpoints[0].Subtag.IsKnown())
+}
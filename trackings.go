@@ -2,6 +2,7 @@ package aftership
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -191,6 +192,17 @@ func (stn SlugTrackingNumber) URIPath() (string, error) {
 	return fmt.Sprintf("/%s/%s", url.PathEscape(stn.Slug), url.PathEscape(stn.TrackingNumber)), nil
 }
 
+// slugOf returns the courier slug of the identifier, if it has one
+func slugOf(identifier TrackingIdentifier) string {
+	switch stn := identifier.(type) {
+	case SlugTrackingNumber:
+		return stn.Slug
+	case *SlugTrackingNumber:
+		return stn.Slug
+	}
+	return ""
+}
+
 // Tracking represents a Tracking returned by the AfterShip API
 type Tracking struct {
 	/**
@@ -270,7 +282,7 @@ type Tracking struct {
 	/**
 	 * Expected delivery date (nullable). Available format: YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SS+TIMEZONE
 	 */
-	ExpectedDelivery string `json:"expected_delivery,omitempty"`
+	ExpectedDelivery LocalTime `json:"expected_delivery,omitempty"`
 
 	/**
 	 * Text field for the note.
@@ -305,12 +317,12 @@ type Tracking struct {
 	/**
 	 * Date and time the tracking was picked up
 	 */
-	ShipmentPickupDate string `json:"shipment_pickup_date,omitempty"`
+	ShipmentPickupDate LocalTime `json:"shipment_pickup_date,omitempty"`
 
 	/**
 	 * Date and time the tracking was delivered
 	 */
-	ShipmentDeliveryDate string `json:"shipment_delivery_date,omitempty"`
+	ShipmentDeliveryDate LocalTime `json:"shipment_delivery_date,omitempty"`
 
 	/**
 	 * Shipment type provided by carrier (if any).
@@ -345,12 +357,12 @@ type Tracking struct {
 	/**
 	 * Current status of tracking.
 	 */
-	Tag string `json:"tag,omitempty"`
+	Tag Tag `json:"tag,omitempty"`
 
 	/**
 	 * Current subtag of tracking. (See subtag definition)
 	 */
-	Subtag string `json:"subtag,omitempty"`
+	Subtag Subtag `json:"subtag,omitempty"`
 
 	/**
 	 * Current status of tracking.
@@ -430,7 +442,7 @@ type Tracking struct {
 	/**
 	 * date and time of the first attempt by the carrier to deliver the package to the addressee. Available format: YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SS+TIMEZONE
 	 */
-	FirstAttemptedAt string `json:"first_attempted_at,omitempty"`
+	FirstAttemptedAt LocalTime `json:"first_attempted_at,omitempty"`
 
 	/**
 	 * Delivery instructions (delivery date or address) can be modified by visiting the link if supported by a carrier.
@@ -502,6 +514,24 @@ type Tracking struct {
 	ProofOfDelivery []ProofOfDelivery `json:"proof_of_delivery"`
 }
 
+// MarshalJSON encodes the tracking, leaving out the missing local times as they were when decoded
+func (tracking Tracking) MarshalJSON() ([]byte, error) {
+	type trackingFields Tracking
+	return json.Marshal(struct {
+		trackingFields
+		ExpectedDelivery     *LocalTime `json:"expected_delivery,omitempty"`
+		ShipmentPickupDate   *LocalTime `json:"shipment_pickup_date,omitempty"`
+		ShipmentDeliveryDate *LocalTime `json:"shipment_delivery_date,omitempty"`
+		FirstAttemptedAt     *LocalTime `json:"first_attempted_at,omitempty"`
+	}{
+		trackingFields:       trackingFields(tracking),
+		ExpectedDelivery:     omitEmptyLocalTime(tracking.ExpectedDelivery),
+		ShipmentPickupDate:   omitEmptyLocalTime(tracking.ShipmentPickupDate),
+		ShipmentDeliveryDate: omitEmptyLocalTime(tracking.ShipmentDeliveryDate),
+		FirstAttemptedAt:     omitEmptyLocalTime(tracking.FirstAttemptedAt),
+	})
+}
+
 type ProofOfDelivery struct {
 	Type string `json:"type"`
 	Url  string `json:"url"`
@@ -526,7 +556,7 @@ type EstimatedDelivery struct {
 type Checkpoint struct {
 	Slug           string     `json:"slug,omitempty"`
 	CreatedAt      *time.Time `json:"created_at,omitempty"`
-	CheckpointTime string     `json:"checkpoint_time,omitempty"`
+	CheckpointTime LocalTime  `json:"checkpoint_time,omitempty"`
 	City           string     `json:"city,omitempty"`
 	Coordinates    []float32  `json:"coordinates,omitempty"`
 	CountryISO3    string     `json:"country_iso3,omitempty"`
@@ -534,13 +564,25 @@ type Checkpoint struct {
 	Message        string     `json:"message,omitempty"`
 	State          string     `json:"state,omitempty"`
 	Location       string     `json:"location,omitempty"`
-	Tag            string     `json:"tag,omitempty"`
-	Subtag         string     `json:"subtag,omitempty"`
+	Tag            Tag        `json:"tag,omitempty"`
+	Subtag         Subtag     `json:"subtag,omitempty"`
 	SubtagMessage  string     `json:"subtag_message,omitempty"`
 	Zip            string     `json:"zip,omitempty"`
 	RawTag         string     `json:"raw_tag,omitempty"`
 }
 
+// MarshalJSON encodes the checkpoint, leaving out the checkpoint time if it is missing
+func (checkpoint Checkpoint) MarshalJSON() ([]byte, error) {
+	type checkpointFields Checkpoint
+	return json.Marshal(struct {
+		checkpointFields
+		CheckpointTime *LocalTime `json:"checkpoint_time,omitempty"`
+	}{
+		checkpointFields: checkpointFields(checkpoint),
+		CheckpointTime:   omitEmptyLocalTime(checkpoint.CheckpointTime),
+	})
+}
+
 type AdditionalField struct {
 	/**
 	 * Account number of the shipper for a specific courier. Required by some couriers, such as dynamic-logistics
@@ -786,6 +828,83 @@ type GetTrackingsParams struct {
 	UpdatedAtMin string `url:"updated_at_min,omitempty" json:"updated_at_min,omitempty"`
 }
 
+// trackingsRetention is how long AfterShip stores the trackings
+const trackingsRetention = 90 * 24 * time.Hour
+
+// retentionSkew allows the created_at range to start slightly before the retention,
+// such as when it is computed before the request is sent.
+const retentionSkew = time.Minute
+
+// WithCreatedAt returns a copy of params matching the trackings created between min and max.
+// A zero min or max is left unset, so that the API defaults apply.
+func (params GetTrackingsParams) WithCreatedAt(min, max time.Time) GetTrackingsParams {
+	params.CreatedAtMin = formatParamTime(min)
+	params.CreatedAtMax = formatParamTime(max)
+	return params
+}
+
+// WithUpdatedAt returns a copy of params matching the trackings updated between min and max.
+// A zero min or max is left unset.
+func (params GetTrackingsParams) WithUpdatedAt(min, max time.Time) GetTrackingsParams {
+	params.UpdatedAtMin = formatParamTime(min)
+	params.UpdatedAtMax = formatParamTime(max)
+	return params
+}
+
+// Validate checks that the created_at and the updated_at ranges are RFC3339 times, that their min is not after their max,
+// and that the created_at range doesn't start before the 90 days retention of the trackings.
+func (params GetTrackingsParams) Validate() error {
+	createdAtMin, createdAtMax, err := parseParamRange("created_at", params.CreatedAtMin, params.CreatedAtMax)
+	if err != nil {
+		return err
+	}
+	if _, _, err := parseParamRange("updated_at", params.UpdatedAtMin, params.UpdatedAtMax); err != nil {
+		return err
+	}
+
+	retention := time.Now().Add(-trackingsRetention - retentionSkew)
+	if createdAtMin != nil && createdAtMin.Before(retention) {
+		return errors.Errorf("created_at_min %s is beyond the 90 days retention", params.CreatedAtMin)
+	}
+	if createdAtMax != nil && createdAtMax.Before(retention) {
+		return errors.Errorf("created_at_max %s is beyond the 90 days retention", params.CreatedAtMax)
+	}
+	return nil
+}
+
+// formatParamTime formats t as a query parameter, an empty string for the zero time.
+func formatParamTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseParamRange parses the RFC3339 min and max of a range, and checks that min is not after max.
+// The unset bounds are nil.
+func parseParamRange(name, min, max string) (*time.Time, *time.Time, error) {
+	var minTime, maxTime *time.Time
+	if min != "" {
+		t, err := time.Parse(time.RFC3339, min)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid %s_min", name)
+		}
+		minTime = &t
+	}
+	if max != "" {
+		t, err := time.Parse(time.RFC3339, max)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid %s_max", name)
+		}
+		maxTime = &t
+	}
+
+	if minTime != nil && maxTime != nil && minTime.After(*maxTime) {
+		return nil, nil, errors.Errorf("%s_min %s is after %s_max %s", name, min, name, max)
+	}
+	return minTime, maxTime, nil
+}
+
 // PagedTrackings is a model for data part of the multiple trackings API responses
 type PagedTrackings struct {
 	Limit                         int        `json:"limit"`   // Number of trackings each page contain. (Default: 100)
@@ -833,7 +952,7 @@ func (client *Client) CreateTracking(ctx context.Context, params CreateTrackingP
 	}
 
 	var trackingWrapper trackingWrapper
-	err := client.makeRequest(ctx, http.MethodPost, "/trackings", nil,
+	err := client.makeRequest(withOperation(ctx, "CreateTracking", params.Slug), http.MethodPost, "/trackings", nil,
 		&createTrackingRequest{Tracking: params}, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
@@ -847,14 +966,23 @@ func (client *Client) DeleteTracking(ctx context.Context, identifier TrackingIde
 
 	uriPath = fmt.Sprintf("/trackings%s", uriPath)
 	var trackingWrapper trackingWrapper
-	err = client.makeRequest(ctx, http.MethodDelete, uriPath, nil, nil, &trackingWrapper)
+	err = client.makeRequest(withOperation(ctx, "DeleteTracking", slugOf(identifier)), http.MethodDelete, uriPath, nil, nil, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
 
 // GetTrackings gets tracking results of multiple trackings.
 func (client *Client) GetTrackings(ctx context.Context, params GetTrackingsParams) (PagedTrackings, error) {
 	var pagedTrackings PagedTrackings
-	err := client.makeRequest(ctx, http.MethodGet, "/trackings", params, nil, &pagedTrackings)
+	if err := params.Validate(); err != nil {
+		return pagedTrackings, &APIError{
+			Code:    codeBadParam,
+			Message: err.Error(),
+			Path:    "/trackings",
+			err:     err,
+		}
+	}
+
+	err := client.makeRequest(withOperation(ctx, "GetTrackings", params.Slug), http.MethodGet, "/trackings", params, nil, &pagedTrackings)
 	return pagedTrackings, err
 }
 
@@ -867,7 +995,7 @@ func (client *Client) GetTracking(ctx context.Context, identifier TrackingIdenti
 
 	uriPath = fmt.Sprintf("/trackings%s", uriPath)
 	var trackingWrapper trackingWrapper
-	err = client.makeRequest(ctx, http.MethodGet, uriPath, params, nil, &trackingWrapper)
+	err = client.makeRequest(withOperation(ctx, "GetTracking", slugOf(identifier)), http.MethodGet, uriPath, params, nil, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
 
@@ -885,7 +1013,7 @@ func (client *Client) UpdateTracking(ctx context.Context, identifier TrackingIde
 
 	uriPath = fmt.Sprintf("/trackings%s", uriPath)
 	var trackingWrapper trackingWrapper
-	err = client.makeRequest(ctx, http.MethodPut, uriPath, nil,
+	err = client.makeRequest(withOperation(ctx, "UpdateTracking", slugOf(identifier)), http.MethodPut, uriPath, nil,
 		&updateTrackingRequest{params}, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
@@ -899,7 +1027,7 @@ func (client *Client) RetrackTracking(ctx context.Context, identifier TrackingId
 
 	uriPath = fmt.Sprintf("/trackings%s/retrack", uriPath)
 	var trackingWrapper trackingWrapper
-	err = client.makeRequest(ctx, http.MethodPost, uriPath, nil, nil, &trackingWrapper)
+	err = client.makeRequest(withOperation(ctx, "RetrackTracking", slugOf(identifier)), http.MethodPost, uriPath, nil, nil, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
 
@@ -917,7 +1045,7 @@ func (client *Client) MarkTrackingAsCompleted(ctx context.Context, identifier Tr
 
 	uriPath = fmt.Sprintf("/trackings%s/mark-as-completed", uriPath)
 	var trackingWrapper trackingWrapper
-	err = client.makeRequest(ctx, http.MethodPost, uriPath,
+	err = client.makeRequest(withOperation(ctx, "MarkTrackingAsCompleted", slugOf(identifier)), http.MethodPost, uriPath,
 		nil, &markAsCompletedRequest{Reason: string(status)}, &trackingWrapper)
 	return trackingWrapper.Tracking, err
 }
@@ -0,0 +1,113 @@
+package aftership
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultExportWindow is the created_at range exported when none is given, as the API defaults to the last 30 days
+const defaultExportWindow = 30 * 24 * time.Hour
+
+// ExportTrackings calls fn with every tracking matching params, beyond the 10,000 trackings matched by a query.
+// The created_at range of params is split in halves until each of them matches less than 10,000 trackings,
+// the trackings are streamed as the pages are fetched, and the trackings created on the bound of two halves
+// are deduplicated by id.
+// The range defaults to the last 30 days. params.Page is ignored. The export stops at the first error of fn.
+func (client *Client) ExportTrackings(ctx context.Context, params GetTrackingsParams, fn func(Tracking) error) error {
+	max := time.Now().UTC()
+	if params.CreatedAtMax != "" {
+		t, err := time.Parse(time.RFC3339, params.CreatedAtMax)
+		if err != nil {
+			return errors.Wrap(err, "invalid created_at_max")
+		}
+		max = t
+	}
+
+	min := max.Add(-defaultExportWindow)
+	if params.CreatedAtMin != "" {
+		t, err := time.Parse(time.RFC3339, params.CreatedAtMin)
+		if err != nil {
+			return errors.Wrap(err, "invalid created_at_min")
+		}
+		min = t
+	}
+
+	exporter := &trackingsExporter{
+		client: client,
+		params: params,
+		fn:     fn,
+	}
+	return exporter.export(ctx, min, max)
+}
+
+type trackingsExporter struct {
+	client *Client
+	params GetTrackingsParams
+	fn     func(Tracking) error
+
+	// boundary holds the ids of the trackings of the previous range created on its max bound,
+	// the only ones which can be matched again by the next range
+	boundary map[string]bool
+}
+
+// export exports the trackings created between min and max
+func (e *trackingsExporter) export(ctx context.Context, min, max time.Time) error {
+	_, err := e.client.iterateRange(ctx, "created", e.params.WithCreatedAt, min, max, e.exportRange)
+	return err
+}
+
+// exportRange exports the trackings of a part of the range, which ends at max
+func (e *trackingsExporter) exportRange(it *TrackingIterator, max time.Time) error {
+	// The range bounds have a second precision and are inclusive
+	boundary := make(map[string]bool)
+	for it.Next() {
+		tracking := it.Tracking()
+		if !e.boundary[tracking.ID] {
+			if err := e.fn(tracking); err != nil {
+				return err
+			}
+		}
+		if tracking.CreatedAt == nil || !tracking.CreatedAt.Before(max.Add(-time.Second)) {
+			boundary[tracking.ID] = true
+		}
+	}
+	e.boundary = boundary
+	return it.Err()
+}
+
+// iterateRange calls fn with an iterator over the trackings of every part of the range from min to max, in order.
+// The range is split in halves while it matches more than the 10,000 trackings returned by the API.
+// withRange returns the params of a part, and field names the range in the errors, such as "created".
+// It returns the number of requests of the parts which were split.
+func (client *Client) iterateRange(ctx context.Context, field string,
+	withRange func(min, max time.Time) GetTrackingsParams, min, max time.Time,
+	fn func(it *TrackingIterator, max time.Time) error) (int, error) {
+	params := withRange(min, max)
+	params.Page = 1
+
+	it := client.IterateTrackings(ctx, params)
+	if !it.Next() {
+		return 0, fn(it, max)
+	}
+
+	if it.Truncated() {
+		// The range bounds have a second precision
+		if max.Sub(min) < 2*time.Second {
+			return 1, errors.Errorf("more than %d trackings %s between %s and %s",
+				maxTrackingsCount, field, formatParamTime(min), formatParamTime(max))
+		}
+
+		mid := min.Add(max.Sub(min) / 2).Truncate(time.Second)
+		before, err := client.iterateRange(ctx, field, withRange, min, mid, fn)
+		if err != nil {
+			return 1 + before, err
+		}
+		after, err := client.iterateRange(ctx, field, withRange, mid, max, fn)
+		return 1 + before + after, err
+	}
+
+	it.unread()
+	return 0, fn(it, max)
+}
@@ -0,0 +1,129 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTrackings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// A tracking is created every second
+	start := time.Now().UTC().Truncate(time.Second).Add(-24 * time.Hour)
+	total := 12000
+
+	requests := 0
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		assert.Equal(t, "dhl", query.Get("slug"))
+		min, err := time.Parse(time.RFC3339, query.Get("created_at_min"))
+		assert.Nil(t, err)
+		max, err := time.Parse(time.RFC3339, query.Get("created_at_max"))
+		assert.Nil(t, err)
+		page, _ := strconv.Atoi(query.Get("page"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		// Both bounds are inclusive
+		var matched []string
+		for i := 0; i < total; i++ {
+			createdAt := start.Add(time.Duration(i) * time.Second)
+			if !createdAt.Before(min) && !createdAt.After(max) {
+				matched = append(matched, fmt.Sprintf(`{"id": "%d", "created_at": "%s"}`, i, createdAt.Format(time.RFC3339)))
+			}
+		}
+
+		count := len(matched)
+		if count > maxTrackingsCount {
+			count = maxTrackingsCount
+			matched = matched[:maxTrackingsCount]
+		}
+		from, to := (page-1)*limit, page*limit
+		if from > len(matched) {
+			from = len(matched)
+		}
+		if to > len(matched) {
+			to = len(matched)
+		}
+		fmt.Fprintf(w, `{
+			"meta": {"code": 200},
+			"data": {"page": %d, "limit": %d, "count": %d, "trackings": [%s]}
+		}`, page, limit, count, strings.Join(matched[from:to], ","))
+	})
+
+	seen := make(map[string]int)
+	params := GetTrackingsParams{Slug: "dhl"}.WithCreatedAt(start, start.Add(time.Duration(total)*time.Second))
+	err := client.ExportTrackings(context.Background(), params, func(tracking Tracking) error {
+		seen[tracking.ID]++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Len(t, seen, total)
+	for id, n := range seen {
+		assert.Equal(t, 1, n, id)
+	}
+	// The first page of the whole range, then the pages of the 6001 and 6000 trackings of the halves
+	assert.Equal(t, 1+31+30, requests)
+
+	// Only the ids of the trackings created on the last bound are kept
+	exporter := &trackingsExporter{client: client, params: params, fn: func(Tracking) error { return nil }}
+	err = exporter.export(context.Background(), start, start.Add(time.Duration(total)*time.Second))
+	assert.Nil(t, err)
+	assert.Len(t, exporter.boundary, 1)
+}
+
+func TestExportTrackingsErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"meta": {"code": 200},
+			"data": {"page": 1, "limit": 200, "count": 2, "trackings": [{"id": "1"}, {"id": "2"}]}
+		}`))
+	})
+
+	// Invalid range
+	err := client.ExportTrackings(context.Background(), GetTrackingsParams{CreatedAtMin: "2024-06-01"}, nil)
+	assert.NotNil(t, err)
+	err = client.ExportTrackings(context.Background(), GetTrackingsParams{CreatedAtMax: "2024-06-01"}, nil)
+	assert.NotNil(t, err)
+
+	// The callback fails
+	calls := 0
+	errStop := errors.New("stop")
+	err = client.ExportTrackings(context.Background(), GetTrackingsParams{}, func(tracking Tracking) error {
+		calls++
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExportTrackingsTooManyInOneSecond(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"meta": {"code": 200},
+			"data": {"page": 1, "limit": 200, "count": %d, "trackings": [{"id": "1"}]}
+		}`, maxTrackingsCount)
+	})
+
+	start := time.Now().Add(-time.Hour)
+	params := GetTrackingsParams{}.WithCreatedAt(start, start.Add(3*time.Second))
+	err := client.ExportTrackings(context.Background(), params, func(tracking Tracking) error {
+		return nil
+	})
+	assert.Contains(t, err.Error(), "more than 10000 trackings created")
+}
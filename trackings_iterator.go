@@ -0,0 +1,138 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// maxTrackingsLimit is the maximum number of trackings of a page
+	maxTrackingsLimit = 200
+
+	// maxTrackingsCount is the maximum number of trackings matched by a query
+	maxTrackingsCount = 10000
+)
+
+// TrackingIterator iterates over the trackings matching GetTrackingsParams, fetching the pages lazily.
+//
+//	it := client.IterateTrackings(ctx, aftership.GetTrackingsParams{Tag: "Delivered"})
+//	for it.Next() {
+//		tracking := it.Tracking()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type TrackingIterator struct {
+	// OnRateLimitWait is called before waiting for the rate limit to reset, when a page can't be fetched
+	// because the rate limit is exceeded. It is optional.
+	OnRateLimitWait func(wait time.Duration)
+
+	client    *Client
+	ctx       context.Context
+	params    GetTrackingsParams
+	trackings []Tracking
+	tracking  Tracking
+	count     int
+	done      bool
+	err       error
+}
+
+// IterateTrackings returns an iterator over the trackings matching params, starting at params.Page.
+// params.Limit defaults to the maximum of 200 trackings per page. As the API matches at most 10,000 trackings,
+// the iteration stops at the 10,000th one, see TrackingIterator.Truncated.
+func (client *Client) IterateTrackings(ctx context.Context, params GetTrackingsParams) *TrackingIterator {
+	if params.Limit <= 0 || params.Limit > maxTrackingsLimit {
+		params.Limit = maxTrackingsLimit
+	}
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+
+	return &TrackingIterator{
+		client: client,
+		ctx:    ctx,
+		params: params,
+	}
+}
+
+// Next advances to the next tracking, fetching the next page if needed.
+// It returns false at the end of the iteration or on error.
+func (it *TrackingIterator) Next() bool {
+	for len(it.trackings) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.tracking = it.trackings[0]
+	it.trackings = it.trackings[1:]
+	return true
+}
+
+// unread makes the next call of Next return the current tracking again
+func (it *TrackingIterator) unread() {
+	it.trackings = append([]Tracking{it.tracking}, it.trackings...)
+}
+
+// Tracking returns the current tracking
+func (it *TrackingIterator) Tracking() Tracking {
+	return it.tracking
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *TrackingIterator) Err() error {
+	return it.err
+}
+
+// Count returns the number of trackings matched by the query, as reported by the last fetched page.
+// It is capped at 10,000 by the API.
+func (it *TrackingIterator) Count() int {
+	return it.count
+}
+
+// Truncated reports whether the query matched more trackings than the API returns,
+// after the first page is fetched. Narrow the query, such as its created_at range, to get all of them.
+func (it *TrackingIterator) Truncated() bool {
+	return it.count >= maxTrackingsCount
+}
+
+// fetch fetches the next page, waiting for the rate limit to reset if it is exceeded.
+func (it *TrackingIterator) fetch() {
+	page, err := it.client.GetTrackings(it.ctx, it.params)
+
+	var tooManyRequests *TooManyRequestsError
+	if errors.As(err, &tooManyRequests) {
+		wait := time.Second
+		if tooManyRequests.RateLimit != nil && tooManyRequests.RateLimit.Reset > 0 {
+			if d := time.Until(tooManyRequests.RateLimit.resetAt()); d > 0 {
+				wait = d
+			}
+		}
+
+		if it.OnRateLimitWait != nil {
+			it.OnRateLimitWait(wait)
+		}
+		if sleepContext(it.ctx, wait) != nil {
+			it.err = err
+		}
+		return
+	}
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.trackings = page.Trackings
+	it.count = page.Count
+
+	count := page.Count
+	if count > maxTrackingsCount {
+		count = maxTrackingsCount
+	}
+	if len(page.Trackings) < it.params.Limit || it.params.Page*it.params.Limit >= count {
+		it.done = true
+	}
+	it.params.Page++
+}
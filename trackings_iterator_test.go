@@ -0,0 +1,157 @@
+package aftership
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handleTrackingPages serves total trackings, reporting count as the number of matched trackings
+func handleTrackingPages(t *testing.T, total, count int, requests *int) {
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		assert.True(t, limit > 0 && limit <= maxTrackingsLimit)
+
+		var trackings []string
+		for i := (page - 1) * limit; i < page*limit && i < total; i++ {
+			trackings = append(trackings, fmt.Sprintf(`{"id": "%d"}`, i))
+		}
+		fmt.Fprintf(w, `{
+			"meta": {"code": 200},
+			"data": {"page": %d, "limit": %d, "count": %d, "trackings": [%s]}
+		}`, page, limit, count, strings.Join(trackings, ","))
+	})
+}
+
+func TestIterateTrackings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	handleTrackingPages(t, 450, 450, &requests)
+
+	it := client.IterateTrackings(context.Background(), GetTrackingsParams{Limit: 1000})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Tracking().ID)
+	}
+	assert.Nil(t, it.Err())
+	assert.Len(t, ids, 450)
+	assert.Equal(t, "0", ids[0])
+	assert.Equal(t, "449", ids[449])
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 450, it.Count())
+	assert.False(t, it.Truncated())
+	assert.False(t, it.Next())
+}
+
+func TestIterateTrackingsExactPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	handleTrackingPages(t, 200, 200, &requests)
+
+	it := client.IterateTrackings(context.Background(), GetTrackingsParams{Limit: 100, Page: 2})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 100, n)
+	assert.Equal(t, 1, requests)
+}
+
+func TestIterateTrackingsCountCap(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	handleTrackingPages(t, 20000, maxTrackingsCount, &requests)
+
+	it := client.IterateTrackings(context.Background(), GetTrackingsParams{})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, maxTrackingsCount, n)
+	assert.Equal(t, maxTrackingsCount/maxTrackingsLimit, requests)
+	assert.True(t, it.Truncated())
+}
+
+func TestIterateTrackingsError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"meta": {"code": 401, "type": "Unauthorized", "message": "Invalid API key."}}`))
+	})
+
+	it := client.IterateTrackings(context.Background(), GetTrackingsParams{})
+	assert.False(t, it.Next())
+	assert.True(t, errors.Is(it.Err(), ErrUnauthorized))
+	assert.False(t, it.Next())
+}
+
+func TestIterateTrackingsRateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("x-ratelimit-reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.Header().Set("x-ratelimit-limit", "10")
+			w.Header().Set("x-ratelimit-remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"meta": {"code": 429, "type": "TooManyRequests"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"page": 1, "limit": 200, "count": 1, "trackings": [{"id": "1"}]}}`))
+	})
+
+	var waits []time.Duration
+	it := client.IterateTrackings(context.Background(), GetTrackingsParams{})
+	it.OnRateLimitWait = func(wait time.Duration) {
+		waits = append(waits, wait)
+	}
+	assert.True(t, it.Next())
+	assert.Equal(t, "1", it.Tracking().ID)
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 2, requests)
+	assert.Len(t, waits, 1)
+	assert.True(t, waits[0] > 0 && waits[0] <= 2*time.Second)
+}
+
+func TestIterateTrackingsRateLimitDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		w.Header().Set("x-ratelimit-limit", "10")
+		w.Header().Set("x-ratelimit-remaining", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"meta": {"code": 429, "type": "TooManyRequests"}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	it := client.IterateTrackings(ctx, GetTrackingsParams{})
+	assert.False(t, it.Next())
+	assert.True(t, errors.Is(it.Err(), ErrTooManyRequests))
+}
@@ -0,0 +1,169 @@
+package aftership
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TrackingsQuery builds the GetTrackingsParams of a query, from lists of values instead of comma separated strings.
+// The values are validated when they are added, and the first error is returned by Params.
+//
+//	params, err := aftership.NewTrackingsQuery().
+//		Slugs("dhl", "ups").
+//		Destinations("USA", "HKG").
+//		Tags(aftership.TagInTransit, aftership.TagOutForDelivery).
+//		Params()
+type TrackingsQuery struct {
+	params GetTrackingsParams
+	err    error
+}
+
+// NewTrackingsQuery returns an empty query
+func NewTrackingsQuery() *TrackingsQuery {
+	return &TrackingsQuery{}
+}
+
+// Params returns the params of the query, or the first invalid value of the query.
+func (q *TrackingsQuery) Params() (GetTrackingsParams, error) {
+	if q.err != nil {
+		return GetTrackingsParams{}, q.err
+	}
+	if err := q.params.Validate(); err != nil {
+		return GetTrackingsParams{}, err
+	}
+	return q.params, nil
+}
+
+// Slugs matches the trackings of the couriers
+func (q *TrackingsQuery) Slugs(slugs ...string) *TrackingsQuery {
+	q.params.Slug = q.join("slug", slugs)
+	return q
+}
+
+// Origins matches the trackings from the countries, as ISO 3166-1 alpha-3 codes
+func (q *TrackingsQuery) Origins(countries ...string) *TrackingsQuery {
+	q.params.Origin = q.joinCountries("origin", countries)
+	return q
+}
+
+// Destinations matches the trackings to the countries, as ISO 3166-1 alpha-3 codes
+func (q *TrackingsQuery) Destinations(countries ...string) *TrackingsQuery {
+	q.params.Destination = q.joinCountries("destination", countries)
+	return q
+}
+
+// CourierDestinationCountries matches the trackings to the countries returned by the couriers,
+// as ISO 3166-1 alpha-3 codes
+func (q *TrackingsQuery) CourierDestinationCountries(countries ...string) *TrackingsQuery {
+	q.params.CourierDestinationCountryIso3 = q.joinCountries("courier_destination_country_iso3", countries)
+	return q
+}
+
+// Tags matches the trackings with the delivery statuses
+func (q *TrackingsQuery) Tags(tags ...Tag) *TrackingsQuery {
+	values := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !tag.IsKnown() {
+			q.fail(errors.Errorf("invalid tag %q", tag))
+		}
+		values = append(values, string(tag))
+	}
+	q.params.Tag = q.join("tag", values)
+	return q
+}
+
+// ShipmentTags matches the trackings with the shipment tags
+func (q *TrackingsQuery) ShipmentTags(tags ...string) *TrackingsQuery {
+	q.params.ShipmentTags = q.join("shipment_tags", tags)
+	return q
+}
+
+// TrackingNumbers matches the trackings with the tracking numbers
+func (q *TrackingsQuery) TrackingNumbers(numbers ...string) *TrackingsQuery {
+	q.params.TrackingNumbers = q.join("tracking_numbers", numbers)
+	return q
+}
+
+// ReturnToSender matches the trackings returned to the sender or not
+func (q *TrackingsQuery) ReturnToSender(values ...bool) *TrackingsQuery {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, strconv.FormatBool(value))
+	}
+	q.params.ReturnToSender = q.join("return_to_sender", strs)
+	return q
+}
+
+// Fields sets the fields to include in the response
+func (q *TrackingsQuery) Fields(fields ...string) *TrackingsQuery {
+	q.params.Fields = q.join("fields", fields)
+	return q
+}
+
+// Keyword searches the content of the tracking fields
+func (q *TrackingsQuery) Keyword(keyword string) *TrackingsQuery {
+	q.params.Keyword = keyword
+	return q
+}
+
+// CreatedAt matches the trackings created between min and max, a zero min or max is left unset
+func (q *TrackingsQuery) CreatedAt(min, max time.Time) *TrackingsQuery {
+	q.params = q.params.WithCreatedAt(min, max)
+	return q
+}
+
+// UpdatedAt matches the trackings updated between min and max, a zero min or max is left unset
+func (q *TrackingsQuery) UpdatedAt(min, max time.Time) *TrackingsQuery {
+	q.params = q.params.WithUpdatedAt(min, max)
+	return q
+}
+
+// Page sets the page and the number of trackings per page, up to 200
+func (q *TrackingsQuery) Page(page, limit int) *TrackingsQuery {
+	if page < 0 || limit < 0 || limit > maxTrackingsLimit {
+		q.fail(errors.Errorf("invalid page %d with limit %d", page, limit))
+	}
+	q.params.Page = page
+	q.params.Limit = limit
+	return q
+}
+
+// fail records the first error of the query
+func (q *TrackingsQuery) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// join joins the values of a multi-value param. The values are trimmed,
+// and can't be empty or contain a comma, as the API has no way to escape it.
+func (q *TrackingsQuery) join(name string, values []string) string {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			q.fail(errors.Errorf("empty %s value", name))
+		}
+		if strings.Contains(value, ",") {
+			q.fail(errors.Errorf("%s value %q contains a comma", name, value))
+		}
+		trimmed = append(trimmed, value)
+	}
+	return strings.Join(trimmed, ",")
+}
+
+// joinCountries joins the ISO 3166-1 alpha-3 codes of a multi-value param, in upper case.
+func (q *TrackingsQuery) joinCountries(name string, countries []string) string {
+	codes := make([]string, 0, len(countries))
+	for _, country := range countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if !iso3Countries[code] {
+			q.fail(errors.Errorf("invalid %s country %q, not an ISO 3166-1 alpha-3 code", name, country))
+		}
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, ",")
+}
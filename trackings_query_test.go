@@ -0,0 +1,74 @@
+package aftership
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackingsQuery(t *testing.T) {
+	min := time.Now().Add(-time.Hour)
+
+	params, err := NewTrackingsQuery().
+		Slugs("dhl", " ups").
+		Origins("usa", "HKG ").
+		Destinations("GBR").
+		CourierDestinationCountries("DEU", "FRA").
+		Tags(TagInTransit, TagOutForDelivery).
+		ShipmentTags("fragile", "gift").
+		TrackingNumbers("RA123456789US", "LE123456789US").
+		ReturnToSender(true, false).
+		Fields("title", "order_id").
+		Keyword("iPhone").
+		CreatedAt(min, time.Time{}).
+		Page(2, 50).
+		Params()
+	assert.Nil(t, err)
+	assert.Equal(t, GetTrackingsParams{
+		Slug:                          "dhl,ups",
+		Origin:                        "USA,HKG",
+		Destination:                   "GBR",
+		CourierDestinationCountryIso3: "DEU,FRA",
+		Tag:                           "InTransit,OutForDelivery",
+		ShipmentTags:                  "fragile,gift",
+		TrackingNumbers:               "RA123456789US,LE123456789US",
+		ReturnToSender:                "true,false",
+		Fields:                        "title,order_id",
+		Keyword:                       "iPhone",
+		CreatedAtMin:                  min.UTC().Format(time.RFC3339),
+		Page:                          2,
+		Limit:                         50,
+	}, params)
+
+	params, err = NewTrackingsQuery().Params()
+	assert.Nil(t, err)
+	assert.Equal(t, GetTrackingsParams{}, params)
+}
+
+func TestTrackingsQueryErrors(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		query *TrackingsQuery
+		err   string
+	}{
+		{"country", NewTrackingsQuery().Destinations("USA", "US"), `invalid destination country "US", not an ISO 3166-1 alpha-3 code`},
+		{"tag", NewTrackingsQuery().Tags(TagDelivered, "Delivred"), `invalid tag "Delivred"`},
+		{"comma", NewTrackingsQuery().ShipmentTags("a,b"), `shipment_tags value "a,b" contains a comma`},
+		{"empty", NewTrackingsQuery().Slugs("dhl", " "), "empty slug value"},
+		{"limit", NewTrackingsQuery().Page(1, 500), "invalid page 1 with limit 500"},
+		{"first error", NewTrackingsQuery().Origins("XXX").Slugs(""), `invalid origin country "XXX", not an ISO 3166-1 alpha-3 code`},
+		{"range", NewTrackingsQuery().UpdatedAt(now, now.Add(-time.Hour)), "updated_at_min"},
+	}
+	for _, cur := range tests {
+		tt := cur
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := tt.query.Params()
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			assert.Equal(t, GetTrackingsParams{}, params)
+		})
+	}
+}
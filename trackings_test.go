@@ -2,6 +2,7 @@ package aftership
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -148,18 +149,18 @@ func TestCreateTracking(t *testing.T) {
 		CustomerName:                  "John doe",
 		DestinationCountryISO3:        "USA",
 		CourierDestinationCountryISO3: "USA",
-		ExpectedDelivery:              "2018-08-16",
+		ExpectedDelivery:              mustParseLocalTime("2018-08-16"),
 		Note:                          "test note",
 		OrderID:                       "123",
 		OrderIDPath:                   "/123",
 		OrderDate:                     "2021-07-26T11:23:51-05:00",
 		OriginCountryISO3:             "USD",
-		ShipmentPickupDate:            "2018-08-16",
-		ShipmentDeliveryDate:          "2018-08-16",
+		ShipmentPickupDate:            mustParseLocalTime("2018-08-16"),
+		ShipmentDeliveryDate:          mustParseLocalTime("2018-08-16"),
 		SignedBy:                      "John Doe",
 		ShipmentWeight:                1,
 		ShipmentWeightUnit:            "kg",
-		FirstAttemptedAt:              "2018-08-16",
+		FirstAttemptedAt:              mustParseLocalTime("2018-08-16"),
 		OnTimeStatus:                  "trending-on-time",
 		OrderTags:                     []string{},
 	}
@@ -465,7 +466,7 @@ func TestGetTrackings(t *testing.T) {
 		Tag:            "InfoReceived",
 		Subtag:         "InfoReceived_001",
 		SubtagMessage:  "Info Received",
-		CheckpointTime: "2018-07-31T10:33:00-04:00",
+		CheckpointTime: mustParseLocalTime("2018-07-31T10:33:00-04:00"),
 		Coordinates:    []float32{},
 		RawTag:         "FPX_L_RPIF",
 		State:          "NY",
@@ -482,7 +483,7 @@ func TestGetTrackings(t *testing.T) {
 		Slug:                          "fedex",
 		Active:                        false,
 		Emails:                        []string{},
-		ExpectedDelivery:              "2018-08-16",
+		ExpectedDelivery:              mustParseLocalTime("2018-08-16"),
 		OriginCountryISO3:             "USA",
 		OrderDate:                     "2021-07-26T11:23:51-05:00",
 		DestinationCountryISO3:        "USA",
@@ -493,8 +494,8 @@ func TestGetTrackings(t *testing.T) {
 		OrderID:                       "123",
 		OrderIDPath:                   "/123",
 		ShipmentPackageCount:          1,
-		ShipmentPickupDate:            "2018-07-31T06:00:00",
-		ShipmentDeliveryDate:          "2018-08-01T17:19:47",
+		ShipmentPickupDate:            mustParseLocalTime("2018-07-31T06:00:00"),
+		ShipmentDeliveryDate:          mustParseLocalTime("2018-08-01T17:19:47"),
 		ShipmentType:                  "FedEx Home Delivery",
 		ShipmentWeightUnit:            "kg",
 		ShipmentWeight:                1,
@@ -519,7 +520,7 @@ func TestGetTrackings(t *testing.T) {
 		ReturnToSender:      false,
 		CourierTrackingLink: "https://www.fedex.com/fedextrack/?tracknumbers=111111111111&cntry_code=us",
 		CourierRedirectLink: "https://www.fedex.com/track?loc=en_US&tracknum=111111111111&requester=WT/trackdetails",
-		FirstAttemptedAt:    "2018-08-01T13:19:47-04:00",
+		FirstAttemptedAt:    mustParseLocalTime("2018-08-01T13:19:47-04:00"),
 		AdditionalField: AdditionalField{
 			TrackingAccountNumber:      "123456",
 			TrackingOriginCountry:      "USA",
@@ -550,7 +551,7 @@ func TestGetTrackings(t *testing.T) {
 		Emails: []string{
 			"asdfasdf@asdf.com",
 		},
-		ExpectedDelivery:          "2018-08-16",
+		ExpectedDelivery:          mustParseLocalTime("2018-08-16"),
 		ShipmentPackageCount:      0,
 		ShipmentWeight:            1,
 		ShipmentWeightUnit:        "kg",
@@ -593,12 +594,12 @@ func TestGetTrackings(t *testing.T) {
 		CourierDestinationCountryISO3: "USA",
 		Note:                          "sample note",
 		OriginCountryISO3:             "USA",
-		ShipmentDeliveryDate:          "2018-08-16",
-		ShipmentPickupDate:            "2018-08-16",
+		ShipmentDeliveryDate:          mustParseLocalTime("2018-08-16"),
+		ShipmentPickupDate:            mustParseLocalTime("2018-08-16"),
 		ShipmentType:                  "FedEx Home Delivery",
 		SignedBy:                      "John Doe",
 		Language:                      "en",
-		FirstAttemptedAt:              "2018-08-16",
+		FirstAttemptedAt:              mustParseLocalTime("2018-08-16"),
 		OrderNumber:                   "1234",
 	}
 
@@ -629,6 +630,71 @@ func TestGetTrackings(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestGetTrackingsTimeParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	min := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	max := min.Add(24 * time.Hour)
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, min.UTC().Format(time.RFC3339), query.Get("created_at_min"))
+		assert.Equal(t, max.UTC().Format(time.RFC3339), query.Get("created_at_max"))
+		assert.Equal(t, min.UTC().Format(time.RFC3339), query.Get("updated_at_min"))
+		assert.Empty(t, query.Get("updated_at_max"))
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"trackings": []}}`))
+	})
+
+	p := GetTrackingsParams{Slug: "dhl"}.WithCreatedAt(min, max).WithUpdatedAt(min, time.Time{})
+	assert.Equal(t, "dhl", p.Slug)
+	_, err := client.GetTrackings(context.Background(), p)
+	assert.Nil(t, err)
+}
+
+func TestGetTrackingsParamsValidate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		params  GetTrackingsParams
+		wantErr bool
+	}{
+		{"no range", GetTrackingsParams{}, false},
+		{"created_at range", GetTrackingsParams{}.WithCreatedAt(now.Add(-time.Hour), now), false},
+		{"created_at_max only", GetTrackingsParams{}.WithCreatedAt(time.Time{}, now), false},
+		{"retention", GetTrackingsParams{}.WithCreatedAt(now.Add(-trackingsRetention), now), false},
+		{"beyond retention", GetTrackingsParams{}.WithCreatedAt(now.Add(-trackingsRetention-time.Hour), now), true},
+		{"created_at_max beyond retention", GetTrackingsParams{}.WithCreatedAt(time.Time{}, now.Add(-100*24*time.Hour)), true},
+		{"created_at_min after created_at_max", GetTrackingsParams{}.WithCreatedAt(now, now.Add(-time.Hour)), true},
+		{"updated_at_min after updated_at_max", GetTrackingsParams{}.WithUpdatedAt(now, now.Add(-time.Hour)), true},
+		{"old updated_at range", GetTrackingsParams{}.WithUpdatedAt(now.Add(-200*24*time.Hour), now), false},
+		{"malformed created_at_min", GetTrackingsParams{CreatedAtMin: "2024-06-01"}, true},
+		{"malformed updated_at_max", GetTrackingsParams{UpdatedAtMax: "yesterday"}, true},
+	}
+	for _, cur := range tests {
+		tt := cur
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.params.Validate() != nil)
+		})
+	}
+}
+
+func TestGetTrackingsInvalidParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+
+	_, err := client.GetTrackings(context.Background(), GetTrackingsParams{CreatedAtMin: "2024-06-01"})
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, codeBadParam, apiErr.Code)
+	assert.Equal(t, "/trackings", apiErr.Path)
+}
+
 func TestGetTracking(t *testing.T) {
 	setup()
 	defer teardown()
@@ -741,7 +807,7 @@ func TestGetTracking(t *testing.T) {
 		Tag:            "InfoReceived",
 		Subtag:         "InfoReceived_001",
 		SubtagMessage:  "Info Received",
-		CheckpointTime: "2018-07-23T01:21:39-05:00",
+		CheckpointTime: mustParseLocalTime("2018-07-23T01:21:39-05:00"),
 		Coordinates:    []float32{},
 		RawTag:         "FPX_L_RPIF",
 	}
@@ -759,8 +825,8 @@ func TestGetTracking(t *testing.T) {
 		Emails:                        []string{},
 		OriginCountryISO3:             "CHN",
 		ShipmentPackageCount:          1,
-		ShipmentPickupDate:            "2018-07-23T08:58:00",
-		ShipmentDeliveryDate:          "2018-07-25T01:10:00",
+		ShipmentPickupDate:            mustParseLocalTime("2018-07-23T08:58:00"),
+		ShipmentDeliveryDate:          mustParseLocalTime("2018-07-25T01:10:00"),
 		ShipmentType:                  "FedEx International Economy",
 		ShipmentTags:                  []string{"test_tag1", "test_tag2"},
 		ShipmentWeight:                4.1,
@@ -785,7 +851,7 @@ func TestGetTracking(t *testing.T) {
 		PickupLocation:            "Flagship Store",
 		CourierTrackingLink:       "https://www.fedex.com/fedextrack/?tracknumbers=111111111111&cntry_code=us",
 		CourierRedirectLink:       "https://www.fedex.com/track?loc=en_US&tracknum=111111111111&requester=WT/trackdetails",
-		FirstAttemptedAt:          "2018-07-25T10:10:00+09:00",
+		FirstAttemptedAt:          mustParseLocalTime("2018-07-25T10:10:00+09:00"),
 	}
 
 	res, err := client.GetTracking(context.Background(), p, GetTrackingParams{})
@@ -925,7 +991,7 @@ func TestUpdateTracking(t *testing.T) {
 		Message:        "Picked up",
 		Tag:            "InTransit",
 		Subtag:         "InTransit_002",
-		CheckpointTime: "2018-07-31T20:47:00",
+		CheckpointTime: mustParseLocalTime("2018-07-31T20:47:00"),
 		Coordinates:    []float32{},
 		State:          "NY",
 		RawTag:         "FPX_L_RPIF",
@@ -942,8 +1008,8 @@ func TestUpdateTracking(t *testing.T) {
 		Emails:               []string{},
 		OriginCountryISO3:    "USA",
 		ShipmentPackageCount: 1,
-		ShipmentPickupDate:   "2018-07-31T06:00:00",
-		ShipmentDeliveryDate: "2018-08-01T17:19:47",
+		ShipmentPickupDate:   mustParseLocalTime("2018-07-31T06:00:00"),
+		ShipmentDeliveryDate: mustParseLocalTime("2018-08-01T17:19:47"),
 		ShipmentType:         "FedEx Home Delivery",
 		ShipmentTags:         []string{"test_tag1", "test_tag2"},
 		ShipmentWeightUnit:   "kg",
@@ -969,7 +1035,7 @@ func TestUpdateTracking(t *testing.T) {
 		PickupNote:                "Contact shop keepers when you arrive our stores for shipment pickup",
 		CourierTrackingLink:       "https://www.fedex.com/fedextrack/?tracknumbers=1111111111111&cntry_code=us",
 		CourierRedirectLink:       "https://www.fedex.com/track?loc=en_US&tracknum=1111111111111&requester=WT/trackdetails",
-		FirstAttemptedAt:          "2018-08-01T17:19:47",
+		FirstAttemptedAt:          mustParseLocalTime("2018-08-01T17:19:47"),
 		Note:                      "note",
 	}
 
@@ -1158,7 +1224,7 @@ func TestMarkTrackingAsCompleted(t *testing.T) {
 		Tag:            "InfoReceived",
 		Subtag:         "InfoReceived_001",
 		SubtagMessage:  "Info Received",
-		CheckpointTime: "2018-07-23T01:21:39-05:00",
+		CheckpointTime: mustParseLocalTime("2018-07-23T01:21:39-05:00"),
 		Coordinates:    []float32{},
 		RawTag:         "FPX_L_RPIF",
 	}
@@ -1176,8 +1242,8 @@ func TestMarkTrackingAsCompleted(t *testing.T) {
 		Emails:                        []string{},
 		OriginCountryISO3:             "CHN",
 		ShipmentPackageCount:          1,
-		ShipmentPickupDate:            "2018-07-23T08:58:00",
-		ShipmentDeliveryDate:          "2018-07-25T01:10:00",
+		ShipmentPickupDate:            mustParseLocalTime("2018-07-23T08:58:00"),
+		ShipmentDeliveryDate:          mustParseLocalTime("2018-07-25T01:10:00"),
 		ShipmentType:                  "FedEx International Economy",
 		ShipmentTags:                  []string{"test_tag1", "test_tag2"},
 		ShipmentWeight:                4,
@@ -1202,7 +1268,7 @@ func TestMarkTrackingAsCompleted(t *testing.T) {
 		PickupLocation:            "Flagship Store",
 		CourierTrackingLink:       "https://www.fedex.com/fedextrack/?tracknumbers=111111111111&cntry_code=us",
 		CourierRedirectLink:       "https://www.fedex.com/track?loc=en_US&tracknum=111111111111&requester=WT/trackdetails",
-		FirstAttemptedAt:          "2018-07-25T10:10:00+09:00",
+		FirstAttemptedAt:          mustParseLocalTime("2018-07-25T10:10:00+09:00"),
 	}
 
 	res, _ := client.MarkTrackingAsCompleted(context.Background(), p, TrackingCompletedStatusLost)
@@ -0,0 +1,171 @@
+package aftership
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors returned by VerifyRequest
+var (
+	ErrMissingSignature    = errors.New("aftership: missing request signature")
+	ErrInvalidSignature    = errors.New("aftership: invalid request signature")
+	ErrUnexpectedSignature = errors.New("aftership: request signed with another algorithm than the configured one")
+	ErrMissingDate         = errors.New("aftership: missing or invalid date header")
+	ErrDateSkew            = errors.New("aftership: date header outside of the allowed skew")
+	ErrAuthenticationType  = errors.New("aftership: the authentication type must be AES or RSA")
+)
+
+// defaultMaxSkew is the default maximum difference between the date header of a request and the current time
+const defaultMaxSkew = 5 * time.Minute
+
+// VerifyOptions configures VerifyRequest
+type VerifyOptions struct {
+	// AuthenticationType is the algorithm the requests must be signed with, AES or RSA. It is required:
+	// a request signed with the other algorithm is rejected, whatever its signature.
+	AuthenticationType AuthenticationType
+
+	// MaxSkew is the maximum difference between the date header of the request and the current time.
+	// Defaults to 5 minutes.
+	MaxSkew time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// VerifyRequest verifies the signature of a request signed by the client, such as in a proxy or a fake AfterShip server.
+// secret is the AES api secret when opts.AuthenticationType is AES, checked against the as-signature-hmac-sha256 header,
+// or the PEM encoded RSA public key when it is RSA, checked against the as-signature-rsa-sha256 header.
+// The body of req is read and restored.
+func VerifyRequest(req *http.Request, secret []byte, opts VerifyOptions) error {
+	var header, otherHeader string
+	switch opts.AuthenticationType {
+	case AES:
+		header, otherHeader = HeaderAsSignatureHMAC, HeaderAsSignatureRSA
+	case RSA:
+		header, otherHeader = HeaderAsSignatureRSA, HeaderAsSignatureHMAC
+	default:
+		return ErrAuthenticationType
+	}
+
+	// The algorithm is never taken from the request, so that a public key can't be used as an HMAC secret
+	if req.Header.Get(otherHeader) != "" {
+		return ErrUnexpectedSignature
+	}
+	signature := req.Header.Get(header)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	if opts.MaxSkew <= 0 {
+		opts.MaxSkew = defaultMaxSkew
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	date := req.Header.Get("date")
+	signedAt, err := http.ParseTime(date)
+	if err != nil {
+		return ErrMissingDate
+	}
+	if skew := opts.Now().Sub(signedAt); skew > opts.MaxSkew || skew < -opts.MaxSkew {
+		return ErrDateSkew
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	asHeaders := make(map[string]string)
+	for key, value := range req.Header {
+		if strings.HasPrefix(strings.ToLower(key), "as-signature-") {
+			continue
+		}
+		asHeaders[key] = value[0]
+	}
+
+	signString, err := getRequestSignString(asHeaders, req.Header.Get("Content-Type"),
+		req.URL.RequestURI(), req.Method, date, string(body))
+	if err != nil {
+		return err
+	}
+
+	if opts.AuthenticationType == RSA {
+		return verifyRSASignature(signString, signature, secret)
+	}
+	return verifyHMACSignature(signString, signature, secret)
+}
+
+func verifyHMACSignature(signString, signature string, secret []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(signString))
+	if !hmac.Equal(decoded, h.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func verifyRSASignature(signString, signature string, publicKey []byte) error {
+	key, err := ParseRSAPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	hashed := sha256.Sum256([]byte(signString))
+	err = rsa.VerifyPSS(key, crypto.SHA256, hashed[:], decoded, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseRSAPublicKey parses a PEM encoded PKIX or PKCS#1 RSA public key
+func ParseRSAPublicKey(publicKey []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("invalid RSA public key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("invalid RSA public key: not a PKIX or PKCS#1 key")
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("invalid RSA public key: not an RSA key")
+	}
+	return rsaKey, nil
+}
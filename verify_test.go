@@ -0,0 +1,153 @@
+package aftership
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name               string
+		signer             Signer
+		authenticationType AuthenticationType
+		secret             []byte
+	}{
+		{"HMAC", NewHMACSigner([]byte("YOUR_API_SECRET")), AES, []byte("YOUR_API_SECRET")},
+		{"RSA PKIX", NewRSASigner(key), RSA, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})},
+		{"RSA PKCS1", NewRSASigner(key), RSA, pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})},
+	}
+
+	var secret []byte
+	var opts VerifyOptions
+	var verified error
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		verified = VerifyRequest(r, secret, opts)
+
+		// The body is restored
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		assert.Contains(t, string(body), "1234567890")
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"meta": {"code": 201}, "data": {"tracking": {}}}`))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.Config.Signer = tt.signer
+			secret = tt.secret
+			opts = VerifyOptions{AuthenticationType: tt.authenticationType}
+			verified = ErrMissingSignature
+
+			ctx := WithHeader(context.Background(), "as-store-id", "store-1")
+			_, err := client.CreateTracking(ctx, CreateTrackingParams{TrackingNumber: "1234567890"})
+			assert.Nil(t, err)
+			assert.Nil(t, verified)
+		})
+	}
+}
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	req, err := http.NewRequest(http.MethodPost, "https://api.aftership.com/tracking/2023-10/trackings?lang=en", strings.NewReader(body))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("as-api-key", "YOUR_API_KEY")
+
+	client := &Client{Config: Config{Signer: NewHMACSigner([]byte("YOUR_API_SECRET"))}}
+	assert.Nil(t, client.sign(req, "application/json", body))
+	return req
+}
+
+func TestVerifyRequestErrors(t *testing.T) {
+	secret := []byte("YOUR_API_SECRET")
+	opts := VerifyOptions{AuthenticationType: AES}
+
+	req := signedRequest(t, `{"tracking": {}}`)
+	assert.Nil(t, VerifyRequest(req, secret, opts))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(req, []byte("WRONG_SECRET"), opts))
+
+	// Tampered body
+	req = signedRequest(t, `{"tracking": {}}`)
+	req.Body = ioutil.NopCloser(strings.NewReader(`{"tracking": {"slug": "dhl"}}`))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(req, secret, opts))
+
+	// Tampered header
+	req = signedRequest(t, "")
+	req.Header.Set("as-api-key", "ANOTHER_API_KEY")
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(req, secret, opts))
+
+	// Invalid signature encoding
+	req = signedRequest(t, "")
+	req.Header.Set(HeaderAsSignatureHMAC, "not base64")
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(req, secret, opts))
+
+	// Missing signature
+	req = signedRequest(t, "")
+	req.Header.Del(HeaderAsSignatureHMAC)
+	assert.Equal(t, ErrMissingSignature, VerifyRequest(req, secret, opts))
+
+	// Missing date
+	req = signedRequest(t, "")
+	req.Header.Del("date")
+	assert.Equal(t, ErrMissingDate, VerifyRequest(req, secret, opts))
+
+	// Date skew
+	req = signedRequest(t, "")
+	later := func() time.Time { return time.Now().Add(10 * time.Minute) }
+	assert.Equal(t, ErrDateSkew, VerifyRequest(req, secret, VerifyOptions{AuthenticationType: AES, Now: later}))
+	assert.Nil(t, VerifyRequest(req, secret, VerifyOptions{AuthenticationType: AES, Now: later, MaxSkew: 15 * time.Minute}))
+
+	// Missing authentication type
+	req = signedRequest(t, "")
+	assert.Equal(t, ErrAuthenticationType, VerifyRequest(req, secret, VerifyOptions{}))
+
+	// Signed with another algorithm than the configured one
+	req = signedRequest(t, "")
+	req.Header.Set(HeaderAsSignatureRSA, req.Header.Get(HeaderAsSignatureHMAC))
+	assert.Equal(t, ErrUnexpectedSignature, VerifyRequest(req, secret, opts))
+
+	// Invalid RSA public key
+	req = signedRequest(t, "")
+	req.Header.Set(HeaderAsSignatureRSA, req.Header.Get(HeaderAsSignatureHMAC))
+	req.Header.Del(HeaderAsSignatureHMAC)
+	assert.NotNil(t, VerifyRequest(req, secret, VerifyOptions{AuthenticationType: RSA}))
+}
+
+func TestVerifyRequestForgedHMACWithPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	// The public key is public, anyone can compute an HMAC with it
+	req, err := http.NewRequest(http.MethodPost, "https://api.aftership.com/tracking/2023-10/trackings", strings.NewReader(`{"tracking": {}}`))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	client := &Client{Config: Config{Signer: NewHMACSigner(publicPEM)}}
+	assert.Nil(t, client.sign(req, "application/json", `{"tracking": {}}`))
+
+	assert.Equal(t, ErrUnexpectedSignature, VerifyRequest(req, publicPEM, VerifyOptions{AuthenticationType: RSA}))
+
+	// Along with a bogus RSA signature
+	req.Header.Set(HeaderAsSignatureRSA, "Zm9yZ2Vk")
+	assert.Equal(t, ErrUnexpectedSignature, VerifyRequest(req, publicPEM, VerifyOptions{AuthenticationType: RSA}))
+}
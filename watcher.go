@@ -0,0 +1,348 @@
+package aftership
+
+import (
+	"container/list"
+	"context"
+	"time"
+)
+
+const (
+	// defaultWatchInterval is the default interval between two polls of a watcher
+	defaultWatchInterval = time.Minute
+
+	// defaultMaxWatchInterval is the default maximum interval between two polls, when backing off
+	defaultMaxWatchInterval = 15 * time.Minute
+
+	// watchOverlap is the overlap of the updated_at ranges of two polls, so that no update is missed
+	// because of the clock skew or the second precision of the range
+	watchOverlap = time.Minute
+
+	// watchRateLimitShare is the share of the rate limit used by a watcher
+	watchRateLimitShare = 0.5
+
+	// defaultMaxWatchedTrackings is the default number of snapshots kept by a watcher
+	defaultMaxWatchedTrackings = 10000
+)
+
+// ChangeType is the type of a change of a tracking
+type ChangeType string
+
+// Types of the changes of the trackings
+const (
+	// ChangeNewTracking is a tracking seen for the first time by the watcher
+	ChangeNewTracking ChangeType = "new_tracking"
+
+	// ChangeCheckpoint is a new checkpoint of a tracking
+	ChangeCheckpoint ChangeType = "checkpoint"
+
+	// ChangeStatus is a change of the tag or the subtag of a tracking
+	ChangeStatus ChangeType = "status"
+
+	// ChangeEstimatedDelivery is a change of the latest or the AfterShip estimated delivery of a tracking
+	ChangeEstimatedDelivery ChangeType = "estimated_delivery"
+
+	// ChangeNextCourier is a new next courier of a tracking
+	ChangeNextCourier ChangeType = "next_courier"
+)
+
+// TrackingChange is a change of a tracking detected by a Watcher
+type TrackingChange struct {
+	Type ChangeType
+
+	// Tracking is the tracking after the change, and Previous the tracking before it.
+	// Previous is empty for ChangeNewTracking.
+	Tracking Tracking
+	Previous Tracking
+
+	// Checkpoint is the new checkpoint of a ChangeCheckpoint
+	Checkpoint *Checkpoint
+
+	// NextCourier is the new next courier of a ChangeNextCourier
+	NextCourier *NextCourier
+}
+
+// Watcher polls the trackings, for the accounts without webhooks, and delivers their changes on a channel.
+// It keeps the last snapshot of the most recently seen trackings, see MaxTrackings.
+//
+// The trackings matching params are polled with GetTrackings, from the time of the previous poll,
+// and the watched trackings are polled with GetTracking. The updated_at range of a poll is split in halves
+// while it matches more than the 10,000 trackings returned by the API, as in ExportTrackings.
+// The interval between two polls grows with the number of requests of a poll, so that the watcher uses
+// at most half of the rate limit of the client, and backs off on errors.
+//
+//	watcher := client.NewWatcher(&aftership.GetTrackingsParams{Slug: "dhl"})
+//	watcher.OnError = func(err error) {
+//		log.Println(err)
+//	}
+//	for change := range watcher.Run(ctx) {
+//		fmt.Println(change.Type, change.Tracking.TrackingNumber)
+//	}
+type Watcher struct {
+	// Interval is the minimum interval between two polls, it defaults to 1 minute.
+	Interval time.Duration
+
+	// MaxInterval is the maximum interval between two polls when backing off, it defaults to 15 minutes.
+	MaxInterval time.Duration
+
+	// MaxTrackings is the maximum number of snapshots kept, it defaults to 10,000. The snapshot of the least
+	// recently seen tracking is dropped first, and the tracking is delivered as a ChangeNewTracking
+	// if it is seen again.
+	MaxTrackings int
+
+	// OnError is called with the errors of the polls, which are retried at the next poll. It is optional.
+	OnError func(err error)
+
+	client      *Client
+	params      *GetTrackingsParams
+	identifiers []TrackingIdentifier
+	snapshots   map[string]*list.Element
+	order       *list.List
+	updatedMin  time.Time
+	failures    int
+}
+
+// NewWatcher returns a watcher of the trackings matching params, and of the trackings identified by identifiers.
+// A nil params only polls the identified trackings. params.UpdatedAtMin defaults to the time Run is called.
+func (client *Client) NewWatcher(params *GetTrackingsParams, identifiers ...TrackingIdentifier) *Watcher {
+	return &Watcher{
+		client:      client,
+		params:      params,
+		identifiers: identifiers,
+		snapshots:   make(map[string]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// Run polls the trackings in a goroutine until ctx is done, and then closes the returned channel.
+// Run must be called once.
+func (w *Watcher) Run(ctx context.Context) <-chan TrackingChange {
+	if w.params != nil && w.params.UpdatedAtMin != "" {
+		if t, err := time.Parse(time.RFC3339, w.params.UpdatedAtMin); err == nil {
+			w.updatedMin = t
+		}
+	}
+	if w.updatedMin.IsZero() {
+		w.updatedMin = time.Now()
+	}
+
+	changes := make(chan TrackingChange)
+	go func() {
+		defer close(changes)
+		for {
+			requests, err := w.poll(ctx, changes)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				w.failures++
+				if w.OnError != nil {
+					w.OnError(err)
+				}
+			} else {
+				w.failures = 0
+			}
+
+			if sleepContext(ctx, w.interval(requests)) != nil {
+				return
+			}
+		}
+	}()
+	return changes
+}
+
+// poll polls the trackings once, and returns the number of requests sent and the first error
+func (w *Watcher) poll(ctx context.Context, changes chan<- TrackingChange) (int, error) {
+	var requests int
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if w.params != nil {
+		start := time.Now()
+		n, err := w.pollRange(ctx, changes, w.updatedMin.Add(-watchOverlap), start)
+		requests += n
+		if ctx.Err() != nil {
+			return requests, ctx.Err()
+		}
+
+		// The range is polled again if it is not complete
+		if err != nil {
+			fail(err)
+		} else {
+			w.updatedMin = start
+		}
+	}
+
+	for _, identifier := range w.identifiers {
+		requests++
+		tracking, err := w.client.GetTracking(ctx, identifier, GetTrackingParams{})
+		if err != nil {
+			fail(err)
+			continue
+		}
+		if !w.update(ctx, changes, tracking) {
+			return requests, ctx.Err()
+		}
+	}
+
+	return requests, firstErr
+}
+
+// pollRange polls the trackings updated between min and max, splitting the range while it matches too many
+// trackings. It returns the number of requests sent.
+func (w *Watcher) pollRange(ctx context.Context, changes chan<- TrackingChange, min, max time.Time) (int, error) {
+	var requests int
+	split, err := w.client.iterateRange(ctx, "updated", w.params.WithUpdatedAt, min, max,
+		func(it *TrackingIterator, _ time.Time) error {
+			defer func() {
+				requests += pageCount(it.Count(), it.params.Limit)
+			}()
+
+			for it.Next() {
+				if !w.update(ctx, changes, it.Tracking()) {
+					return ctx.Err()
+				}
+			}
+			return it.Err()
+		})
+	return split + requests, err
+}
+
+// update stores the snapshot of a tracking and delivers its changes.
+// It returns false if ctx is done before the changes are delivered.
+func (w *Watcher) update(ctx context.Context, changes chan<- TrackingChange, tracking Tracking) bool {
+	previous, ok := w.snapshot(tracking)
+
+	var detected []TrackingChange
+	if ok {
+		detected = watchChanges(previous, tracking)
+	} else {
+		detected = []TrackingChange{{Type: ChangeNewTracking, Tracking: tracking}}
+	}
+
+	for _, change := range detected {
+		select {
+		case changes <- change:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// snapshot stores the snapshot of a tracking, dropping the least recently seen one beyond MaxTrackings.
+// It returns the previous snapshot, and whether there was one.
+func (w *Watcher) snapshot(tracking Tracking) (Tracking, bool) {
+	if elem, ok := w.snapshots[tracking.ID]; ok {
+		previous := elem.Value.(Tracking)
+		elem.Value = tracking
+		w.order.MoveToFront(elem)
+		return previous, true
+	}
+
+	w.snapshots[tracking.ID] = w.order.PushFront(tracking)
+
+	maxTrackings := w.MaxTrackings
+	if maxTrackings <= 0 {
+		maxTrackings = defaultMaxWatchedTrackings
+	}
+	for w.order.Len() > maxTrackings {
+		oldest := w.order.Back()
+		w.order.Remove(oldest)
+		delete(w.snapshots, oldest.Value.(Tracking).ID)
+	}
+	return Tracking{}, false
+}
+
+// interval returns the interval until the next poll. It leaves half of the rate limit to the other requests
+// of the client, and doubles after every failed poll.
+func (w *Watcher) interval(requests int) time.Duration {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	maxInterval := w.MaxInterval
+	if maxInterval < interval {
+		maxInterval = defaultMaxWatchInterval
+		if maxInterval < interval {
+			maxInterval = interval
+		}
+	}
+
+	if rateLimit := w.client.GetRateLimit(); rateLimit.Limit > 0 {
+		d := time.Duration(float64(requests) / (float64(rateLimit.Limit) * watchRateLimitShare) * float64(time.Second))
+		if d > interval {
+			interval = d
+		}
+	}
+
+	for i := 0; i < w.failures && interval < maxInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
+// pageCount returns the number of pages of count trackings fetched by IterateTrackings, at least one
+func pageCount(count, limit int) int {
+	if limit <= 0 || limit > maxTrackingsLimit {
+		limit = maxTrackingsLimit
+	}
+	if count > maxTrackingsCount {
+		count = maxTrackingsCount
+	}
+	if count <= limit {
+		return 1
+	}
+	return (count + limit - 1) / limit
+}
+
+// watchChanges returns the changes between two snapshots of a tracking
+func watchChanges(previous, tracking Tracking) []TrackingChange {
+	var changes []TrackingChange
+
+	diff := DiffTrackings(previous, tracking)
+	for i := range diff.AddedCheckpoints {
+		changes = append(changes, TrackingChange{
+			Type:       ChangeCheckpoint,
+			Tracking:   tracking,
+			Previous:   previous,
+			Checkpoint: &diff.AddedCheckpoints[i],
+		})
+	}
+
+	_, tagChanged := diff.Field("tag")
+	_, subtagChanged := diff.Field("subtag")
+	if tagChanged || subtagChanged {
+		changes = append(changes, TrackingChange{Type: ChangeStatus, Tracking: tracking, Previous: previous})
+	}
+
+	if previous.LatestEstimatedDelivery != tracking.LatestEstimatedDelivery ||
+		previous.EstimatedDeliveryDate.EstimatedDeliveryDate != tracking.EstimatedDeliveryDate.EstimatedDeliveryDate ||
+		previous.EstimatedDeliveryDate.EstimatedDeliveryDateMin != tracking.EstimatedDeliveryDate.EstimatedDeliveryDateMin ||
+		previous.EstimatedDeliveryDate.EstimatedDeliveryDateMax != tracking.EstimatedDeliveryDate.EstimatedDeliveryDateMax {
+		changes = append(changes, TrackingChange{Type: ChangeEstimatedDelivery, Tracking: tracking, Previous: previous})
+	}
+
+	couriers := make(map[NextCourier]bool, len(previous.NextCouriers))
+	for _, courier := range previous.NextCouriers {
+		couriers[courier] = true
+	}
+	for i := range tracking.NextCouriers {
+		if !couriers[tracking.NextCouriers[i]] {
+			changes = append(changes, TrackingChange{
+				Type:        ChangeNextCourier,
+				Tracking:    tracking,
+				Previous:    previous,
+				NextCourier: &tracking.NextCouriers[i],
+			})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,268 @@
+package aftership
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectChanges receives n changes, or fails after a second
+func collectChanges(t *testing.T, changes <-chan TrackingChange, n int) []TrackingChange {
+	var collected []TrackingChange
+	timeout := time.After(time.Second)
+	for len(collected) < n {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				t.Fatalf("channel closed after %d changes", len(collected))
+			}
+			collected = append(collected, change)
+		case <-timeout:
+			t.Fatalf("received %d changes, expected %d", len(collected), n)
+		}
+	}
+	return collected
+}
+
+func changeTypes(changes []TrackingChange) []ChangeType {
+	types := make([]ChangeType, 0, len(changes))
+	for _, change := range changes {
+		types = append(types, change.Type)
+	}
+	return types
+}
+
+var watchedTrackings = []string{
+	`{"id": "1", "tag": "InTransit", "subtag": "InTransit_001",
+		"checkpoints": [{"checkpoint_time": "2024-03-01T10:00:00", "message": "Picked up", "location": "Hong Kong"}]}`,
+	`{"id": "1", "tag": "Delivered", "subtag": "Delivered_001",
+		"checkpoints": [
+			{"checkpoint_time": "2024-03-01T10:00:00", "message": "Picked up", "location": "Hong Kong"},
+			{"checkpoint_time": "2024-03-03T10:00:00", "message": "Delivered", "location": "New York"}
+		],
+		"latest_estimated_delivery": {"datetime": "2024-03-03"},
+		"next_couriers": [{"slug": "usps", "tracking_number": "123"}]}`,
+}
+
+func TestWatcherParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var updatedAtMins []string
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		updatedAtMins = append(updatedAtMins, r.URL.Query().Get("updated_at_min"))
+		tracking := watchedTrackings[0]
+		if len(updatedAtMins) > 1 {
+			tracking = watchedTrackings[1]
+		}
+		mu.Unlock()
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"page": 1, "limit": 200, "count": 1, "trackings": [` + tracking + `]}}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	watcher := client.NewWatcher(&GetTrackingsParams{Slug: "dhl"})
+	watcher.Interval = 10 * time.Millisecond
+	changes := watcher.Run(ctx)
+
+	collected := collectChanges(t, changes, 5)
+	cancel()
+	for range changes {
+	}
+
+	assert.Equal(t, []ChangeType{
+		ChangeNewTracking,
+		ChangeCheckpoint,
+		ChangeStatus,
+		ChangeEstimatedDelivery,
+		ChangeNextCourier,
+	}, changeTypes(collected))
+
+	assert.Equal(t, "1", collected[0].Tracking.ID)
+	assert.Empty(t, collected[0].Previous.ID)
+	assert.Equal(t, "Delivered", collected[1].Checkpoint.Message)
+	assert.Equal(t, TagInTransit, collected[2].Previous.Tag)
+	assert.Equal(t, TagDelivered, collected[2].Tracking.Tag)
+	assert.Equal(t, "2024-03-03", collected[3].Tracking.LatestEstimatedDelivery.Datetime)
+	assert.Equal(t, "usps", collected[4].NextCourier.Slug)
+
+	mu.Lock()
+	defer mu.Unlock()
+	first, err := time.Parse(time.RFC3339, updatedAtMins[0])
+	assert.Nil(t, err)
+	assert.WithinDuration(t, start.Add(-watchOverlap), first, 2*time.Second)
+}
+
+func TestWatcherIdentifiers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	requests := 0
+	mux.HandleFunc("/trackings/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		tracking := watchedTrackings[0]
+		if requests > 1 {
+			tracking = watchedTrackings[1]
+		}
+		mu.Unlock()
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"tracking": ` + tracking + `}}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := client.NewWatcher(nil, TrackingID("1"))
+	watcher.Interval = 10 * time.Millisecond
+	changes := watcher.Run(ctx)
+
+	collected := collectChanges(t, changes, 5)
+	assert.Equal(t, ChangeNewTracking, collected[0].Type)
+	assert.Equal(t, ChangeNextCourier, collected[4].Type)
+
+	// The next polls have no changes
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected change %s", change.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	_, ok := <-changes
+	assert.False(t, ok)
+}
+
+func TestWatcherError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	requests := 0
+	mux.HandleFunc("/trackings/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"meta": {"code": 500, "type": "InternalError"}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"tracking": {"id": "1"}}}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 1)
+	watcher := client.NewWatcher(nil, TrackingID("1"))
+	watcher.Interval = 10 * time.Millisecond
+	watcher.OnError = func(err error) {
+		errs <- err
+	}
+	changes := watcher.Run(ctx)
+
+	collected := collectChanges(t, changes, 1)
+	assert.Equal(t, ChangeNewTracking, collected[0].Type)
+
+	err := <-errs
+	assert.Contains(t, err.Error(), "InternalError")
+}
+
+func TestWatcherTruncated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The ranges longer than 40 seconds match too many trackings
+	limit := 40 * time.Second
+	mux.HandleFunc("/trackings", func(w http.ResponseWriter, r *http.Request) {
+		min, _ := time.Parse(time.RFC3339, r.URL.Query().Get("updated_at_min"))
+		max, _ := time.Parse(time.RFC3339, r.URL.Query().Get("updated_at_max"))
+		if max.Sub(min) > limit {
+			w.Write([]byte(`{"meta": {"code": 200}, "data": {"page": 1, "limit": 200, "count": 10000, "trackings": [{"id": "truncated"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {"page": 1, "limit": 200, "count": 1, "trackings": [{"id": "` + min.Format(time.RFC3339) + `"}]}}`))
+	})
+
+	watcher := client.NewWatcher(&GetTrackingsParams{Slug: "dhl"})
+	watcher.updatedMin = time.Now()
+	changes := make(chan TrackingChange, 10)
+
+	// The range is split in halves
+	requests, err := watcher.poll(context.Background(), changes)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Len(t, changes, 2)
+	for len(changes) > 0 {
+		change := <-changes
+		assert.Equal(t, ChangeNewTracking, change.Type)
+		assert.NotEqual(t, "truncated", change.Tracking.ID)
+	}
+
+	// Even a range of a second matches too many trackings, the range is not advanced
+	limit = 0
+	updatedMin := watcher.updatedMin
+	_, err = watcher.poll(context.Background(), changes)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "more than 10000 trackings")
+	assert.Equal(t, updatedMin, watcher.updatedMin)
+	assert.Len(t, changes, 0)
+}
+
+func TestWatcherMaxTrackings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	watcher := client.NewWatcher(nil)
+	watcher.MaxTrackings = 2
+
+	_, ok := watcher.snapshot(Tracking{ID: "1"})
+	assert.False(t, ok)
+	watcher.snapshot(Tracking{ID: "2"})
+	previous, ok := watcher.snapshot(Tracking{ID: "1", Tag: TagInTransit})
+	assert.True(t, ok)
+	assert.Equal(t, "1", previous.ID)
+
+	// "2" is dropped as the least recently seen tracking
+	watcher.snapshot(Tracking{ID: "3"})
+	assert.Len(t, watcher.snapshots, 2)
+	_, ok = watcher.snapshot(Tracking{ID: "2"})
+	assert.False(t, ok)
+	previous, ok = watcher.snapshot(Tracking{ID: "3"})
+	assert.True(t, ok)
+	assert.Equal(t, "3", previous.ID)
+}
+
+func TestWatcherInterval(t *testing.T) {
+	setup()
+	defer teardown()
+
+	watcher := client.NewWatcher(nil)
+	assert.Equal(t, defaultWatchInterval, watcher.interval(1))
+
+	watcher.failures = 2
+	assert.Equal(t, 4*defaultWatchInterval, watcher.interval(1))
+
+	watcher.failures = 10
+	assert.Equal(t, defaultMaxWatchInterval, watcher.interval(1))
+
+	watcher.failures = 0
+	watcher.Interval = time.Second
+	client.limiter.rateLimit = RateLimit{Limit: 10, Remaining: 10}
+	assert.Equal(t, time.Second, watcher.interval(4))
+	assert.Equal(t, 10*time.Second, watcher.interval(50))
+}
+
+func TestPageCount(t *testing.T) {
+	assert.Equal(t, 1, pageCount(0, 0))
+	assert.Equal(t, 1, pageCount(200, 0))
+	assert.Equal(t, 2, pageCount(201, 0))
+	assert.Equal(t, 3, pageCount(250, 100))
+	assert.Equal(t, 50, pageCount(20000, 0))
+}
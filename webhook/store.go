@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultStoreSize is the number of trackings remembered by the default store
+const defaultStoreSize = 10000
+
+// Version identifies a logical update of a tracking
+type Version struct {
+	// UpdatedAt is the update time of the tracking.
+	UpdatedAt time.Time
+
+	// Checkpoints is the number of checkpoints of the tracking.
+	Checkpoints int
+}
+
+// After reports whether v is a later update than other. Updates at the same time are ordered by their number of checkpoints.
+func (v Version) After(other Version) bool {
+	if !v.UpdatedAt.Equal(other.UpdatedAt) {
+		return v.UpdatedAt.After(other.UpdatedAt)
+	}
+	return v.Checkpoints > other.Checkpoints
+}
+
+// Store records the last update delivered for every tracking, to drop the duplicated and the stale webhooks.
+type Store interface {
+	// Advance records version as the last update of the tracking if it is after the recorded one,
+	// and reports whether it was recorded. It must be atomic, as webhooks are received concurrently.
+	Advance(ctx context.Context, trackingID string, version Version) (bool, error)
+
+	// Release rolls back the recording of version by Advance, when its delivery failed, so that the retried
+	// webhook is delivered. It does nothing if a later update was recorded since.
+	Release(ctx context.Context, trackingID string, version Version) error
+}
+
+type memoryEntry struct {
+	trackingID string
+	version    Version
+
+	// previous is the version recorded before version, restored by Release
+	previous    Version
+	hasPrevious bool
+}
+
+// memoryStore is a Store keeping the most recently updated trackings in memory
+type memoryStore struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// NewMemoryStore returns a Store keeping the last update of the size most recently updated trackings in memory.
+func NewMemoryStore(size int) Store {
+	if size <= 0 {
+		size = defaultStoreSize
+	}
+	return &memoryStore{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *memoryStore) Advance(ctx context.Context, trackingID string, version Version) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[trackingID]; ok {
+		entry := elem.Value.(*memoryEntry)
+		if !version.After(entry.version) {
+			return false, nil
+		}
+		entry.previous, entry.hasPrevious = entry.version, true
+		entry.version = version
+		s.order.MoveToFront(elem)
+		return true, nil
+	}
+
+	s.entries[trackingID] = s.order.PushFront(&memoryEntry{trackingID: trackingID, version: version})
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).trackingID)
+	}
+	return true, nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, trackingID string, version Version) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[trackingID]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.version.After(version) || version.After(entry.version) {
+		return nil
+	}
+
+	if entry.hasPrevious {
+		entry.version, entry.hasPrevious = entry.previous, false
+		return nil
+	}
+	s.order.Remove(elem)
+	delete(s.entries, trackingID)
+	return nil
+}
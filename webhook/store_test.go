@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionAfter(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, Version{UpdatedAt: now.Add(time.Second)}.After(Version{UpdatedAt: now, Checkpoints: 3}))
+	assert.False(t, Version{UpdatedAt: now, Checkpoints: 3}.After(Version{UpdatedAt: now.Add(time.Second)}))
+	assert.True(t, Version{UpdatedAt: now, Checkpoints: 3}.After(Version{UpdatedAt: now, Checkpoints: 2}))
+	assert.False(t, Version{UpdatedAt: now, Checkpoints: 2}.After(Version{UpdatedAt: now, Checkpoints: 2}))
+	assert.False(t, Version{UpdatedAt: now.UTC()}.After(Version{UpdatedAt: now.Local()}))
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore(2)
+
+	advanced, err := store.Advance(ctx, "a", Version{UpdatedAt: now})
+	assert.Nil(t, err)
+	assert.True(t, advanced)
+
+	// Duplicated
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now})
+	assert.False(t, advanced)
+
+	// Stale
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now.Add(-time.Minute)})
+	assert.False(t, advanced)
+
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now, Checkpoints: 1})
+	assert.True(t, advanced)
+
+	// "b" is evicted as the least recently updated tracking
+	store.Advance(ctx, "b", Version{UpdatedAt: now})
+	store.Advance(ctx, "a", Version{UpdatedAt: now.Add(time.Minute)})
+	store.Advance(ctx, "c", Version{UpdatedAt: now})
+
+	advanced, _ = store.Advance(ctx, "b", Version{UpdatedAt: now})
+	assert.True(t, advanced)
+	advanced, _ = store.Advance(ctx, "c", Version{UpdatedAt: now})
+	assert.False(t, advanced)
+}
+
+func TestMemoryStoreRelease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore(0)
+
+	// The first update of a tracking is forgotten
+	store.Advance(ctx, "a", Version{UpdatedAt: now})
+	assert.Nil(t, store.Release(ctx, "a", Version{UpdatedAt: now}))
+	advanced, _ := store.Advance(ctx, "a", Version{UpdatedAt: now})
+	assert.True(t, advanced)
+
+	// The previous update is restored
+	store.Advance(ctx, "a", Version{UpdatedAt: now, Checkpoints: 1})
+	assert.Nil(t, store.Release(ctx, "a", Version{UpdatedAt: now, Checkpoints: 1}))
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now})
+	assert.False(t, advanced)
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now, Checkpoints: 1})
+	assert.True(t, advanced)
+
+	// A later update is kept
+	store.Advance(ctx, "a", Version{UpdatedAt: now.Add(time.Minute)})
+	assert.Nil(t, store.Release(ctx, "a", Version{UpdatedAt: now, Checkpoints: 1}))
+	advanced, _ = store.Advance(ctx, "a", Version{UpdatedAt: now.Add(time.Minute)})
+	assert.False(t, advanced)
+
+	// Unknown tracking
+	assert.Nil(t, store.Release(ctx, "b", Version{UpdatedAt: now}))
+}
+
+func TestMemoryStoreConcurrency(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore(0)
+
+	var mu sync.Mutex
+	advanced := make(map[string]int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				id := strconv.Itoa(j)
+				if ok, _ := store.Advance(ctx, id, Version{UpdatedAt: now}); ok {
+					mu.Lock()
+					advanced[id]++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, advanced, 10)
+	for id, count := range advanced {
+		assert.Equal(t, 1, count, id)
+	}
+}
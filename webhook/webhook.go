@@ -0,0 +1,216 @@
+/*
+Package webhook receives the AfterShip tracking webhooks.
+
+The Handler verifies the aftership-hmac-sha256 signature of the webhooks with the webhook secret,
+decodes the tracking and dispatches the events to the registered callbacks.
+
+	handler := webhook.NewHandler([]byte("YOUR_WEBHOOK_SECRET"))
+	handler.OnTrackingUpdated(func(ctx context.Context, event *webhook.Event) error {
+		fmt.Println(event.Tracking.TrackingNumber, event.Tracking.Tag)
+		return nil
+	})
+	http.Handle("/webhooks/aftership", handler)
+*/
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+)
+
+// HeaderSignature is the header carrying the HMAC-SHA256 signature of the webhook body
+const HeaderSignature = "aftership-hmac-sha256"
+
+// EventTrackingUpdate is the event of a tracking update webhook
+const EventTrackingUpdate = "tracking_update"
+
+// defaultMaxBodySize is the default maximum size of a webhook body, read before its signature is verified
+const defaultMaxBodySize = 5 << 20
+
+// Event is the payload of a webhook
+type Event struct {
+	// Event is the type of the event, such as "tracking_update".
+	Event string `json:"event"`
+
+	// EventID is the unique identifier of the event.
+	EventID string `json:"event_id"`
+
+	// IsTrackingFirstTag is true if the tracking got its first tag.
+	IsTrackingFirstTag bool `json:"is_tracking_first_tag"`
+
+	// Tracking is the updated tracking.
+	Tracking aftership.Tracking `json:"msg"`
+
+	// Timestamp is the UNIX timestamp of the event.
+	Timestamp int64 `json:"ts"`
+}
+
+// EventFunc handles a webhook event. An error makes the handler respond 500, so that AfterShip retries the webhook:
+// the update is released from the store, so the retry is dispatched again.
+type EventFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler receiving the AfterShip webhooks. It responds
+//   - 405 to non POST requests
+//   - 413 to webhooks larger than the maximum body size, see WithMaxBodySize
+//   - 401 to webhooks without a valid signature
+//   - 400 to webhooks which can't be decoded
+//   - 500 if a callback or the store fails
+//   - 200 otherwise, including for the events without callbacks and the dropped events
+//
+// The duplicated and the stale webhooks of a tracking are dropped, by comparing the updated_at time and the
+// number of checkpoints of the tracking with the last delivered ones. An update is delivered at most once:
+// it is recorded atomically before the callbacks are called, so that concurrent duplicated or older webhooks
+// are dropped, and it is released if they fail, so that the webhook retried by AfterShip is dispatched again.
+type Handler struct {
+	secret          []byte
+	store           Store
+	maxBodySize     int64
+	trackingUpdated []EventFunc
+}
+
+// Option configures the handler
+type Option func(*Handler)
+
+// WithStore sets the store of the last delivered updates, such as a store shared by the replicas of a service.
+// Defaults to an in-memory store of the 10000 most recently updated trackings.
+// A nil store disables the deduplication.
+func WithStore(store Store) Option {
+	return func(h *Handler) {
+		h.store = store
+	}
+}
+
+// WithMaxBodySize sets the maximum size in bytes of a webhook body. The body is read before its signature
+// is verified, so the limit bounds the memory used by unauthenticated requests. Defaults to 5 MiB.
+// A size <= 0 keeps the default.
+func WithMaxBodySize(size int64) Option {
+	return func(h *Handler) {
+		if size > 0 {
+			h.maxBodySize = size
+		}
+	}
+}
+
+// NewHandler returns a Handler verifying the webhooks with the webhook secret.
+// It panics if the secret is empty, as anyone can sign a webhook with an empty key.
+func NewHandler(secret []byte, opts ...Option) *Handler {
+	if len(secret) == 0 {
+		panic("webhook: empty webhook secret")
+	}
+
+	h := &Handler{
+		secret:      secret,
+		store:       NewMemoryStore(defaultStoreSize),
+		maxBodySize: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// OnTrackingUpdated registers a callback of the tracking update events.
+// The callbacks are called in the order they were registered, and must be registered before serving.
+func (h *Handler) OnTrackingUpdated(fn EventFunc) {
+	h.trackingUpdated = append(h.trackingUpdated, fn)
+}
+
+// ServeHTTP verifies, decodes and dispatches a webhook
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		// MaxBytesReader returns exactly maxBodySize bytes before failing on a larger body
+		if int64(len(body)) >= h.maxBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if !VerifySignature(body, r.Header.Get(HeaderSignature), h.secret) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	trackingID, version, ok := h.version(&event)
+	if ok {
+		latest, err := h.store.Advance(r.Context(), trackingID, version)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !latest {
+			// Duplicated or stale webhook
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if err := h.dispatch(r.Context(), &event); err != nil {
+		// The retried webhook is delivered again. If the release fails, it is dropped as a duplicate.
+		if ok {
+			h.store.Release(r.Context(), trackingID, version)
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// version returns the update of the event tracking, and reports whether it is deduplicated.
+// Events without a tracking id or an update time are always delivered.
+func (h *Handler) version(event *Event) (string, Version, bool) {
+	tracking := event.Tracking
+	if h.store == nil || tracking.ID == "" || tracking.UpdatedAt == nil {
+		return "", Version{}, false
+	}
+
+	return tracking.ID, Version{
+		UpdatedAt:   *tracking.UpdatedAt,
+		Checkpoints: len(tracking.Checkpoints),
+	}, true
+}
+
+// dispatch calls the callbacks of the event, until one fails
+func (h *Handler) dispatch(ctx context.Context, event *Event) error {
+	var callbacks []EventFunc
+	if event.Event == EventTrackingUpdate {
+		callbacks = h.trackingUpdated
+	}
+
+	for _, fn := range callbacks {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifySignature reports whether signature is the valid aftership-hmac-sha256 signature of the webhook body.
+// It returns false if the secret is empty.
+func VerifySignature(body []byte, signature string, secret []byte) bool {
+	if signature == "" || len(secret) == 0 {
+		return false
+	}
+	expected := aftership.GetHMACSignature(string(body), secret)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
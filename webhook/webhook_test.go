@@ -0,0 +1,305 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aftership/aftership-sdk-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "YOUR_WEBHOOK_SECRET"
+
+const payload = `{
+	"event": "tracking_update",
+	"event_id": "b3ff34c4-a2d3-4b5c-8ba0-2b2f2ff0d3ab",
+	"is_tracking_first_tag": false,
+	"msg": {
+		"id": "5b7658cec7c33c0e007de3c5",
+		"slug": "dhl",
+		"tracking_number": "1234567890",
+		"tag": "InTransit",
+		"updated_at": "2024-06-11T08:00:00+00:00",
+		"checkpoints": [
+			{"slug": "dhl", "tag": "InTransit", "message": "Departed Facility"}
+		]
+	},
+	"ts": 1718092800
+}`
+
+func newRequest(body, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(HeaderSignature, signature)
+	}
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestTrackingUpdated(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+
+	var events []*Event
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	signature := aftership.GetHMACSignature(payload, []byte(secret))
+	assert.Equal(t, http.StatusOK, serve(handler, newRequest(payload, signature)))
+	assert.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, EventTrackingUpdate, event.Event)
+	assert.Equal(t, "b3ff34c4-a2d3-4b5c-8ba0-2b2f2ff0d3ab", event.EventID)
+	assert.Equal(t, int64(1718092800), event.Timestamp)
+	assert.Equal(t, "5b7658cec7c33c0e007de3c5", event.Tracking.ID)
+	assert.Equal(t, "1234567890", event.Tracking.TrackingNumber)
+	assert.Equal(t, "2024-06-11T08:00:00Z", event.Tracking.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z07:00"))
+	assert.Len(t, event.Tracking.Checkpoints, 1)
+	assert.Equal(t, "Departed Facility", event.Tracking.Checkpoints[0].Message)
+}
+
+func TestRejectedWebhooks(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		t.Error("unexpected event")
+		return nil
+	})
+
+	signature := aftership.GetHMACSignature(payload, []byte(secret))
+
+	// Unsigned
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(payload, "")))
+
+	// Tampered
+	tampered := strings.Replace(payload, "InTransit", "Delivered", 1)
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(tampered, signature)))
+
+	// Signed with another secret
+	other := aftership.GetHMACSignature(payload, []byte("ANOTHER_SECRET"))
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(payload, other)))
+
+	// Invalid JSON
+	invalid := `{"event": `
+	assert.Equal(t, http.StatusBadRequest,
+		serve(handler, newRequest(invalid, aftership.GetHMACSignature(invalid, []byte(secret)))))
+
+	// Not a POST request
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+}
+
+func TestEmptySecret(t *testing.T) {
+	signature := aftership.GetHMACSignature(payload, nil)
+	assert.False(t, VerifySignature([]byte(payload), signature, nil))
+	assert.False(t, VerifySignature([]byte(payload), signature, []byte{}))
+
+	assert.Panics(t, func() { NewHandler(nil) })
+	assert.Panics(t, func() { NewHandler([]byte("")) })
+
+	// A handler built without NewHandler rejects the webhooks signed with an empty key
+	handler := &Handler{maxBodySize: defaultMaxBodySize}
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		t.Error("unexpected event")
+		return nil
+	})
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(payload, signature)))
+}
+
+func TestMaxBodySize(t *testing.T) {
+	handler := NewHandler([]byte(secret), WithMaxBodySize(int64(len(payload))))
+	calls := 0
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+
+	// A body of the maximum size is accepted
+	signature := aftership.GetHMACSignature(payload, []byte(secret))
+	assert.Equal(t, http.StatusOK, serve(handler, newRequest(payload, signature)))
+	assert.Equal(t, 1, calls)
+
+	// A larger body is rejected before its signature is verified
+	large := payload + " "
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(handler, newRequest(large, "")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge,
+		serve(handler, newRequest(large, aftership.GetHMACSignature(large, []byte(secret)))))
+	assert.Equal(t, 1, calls)
+
+	// The default limit
+	handler = NewHandler([]byte(secret))
+	huge := strings.Repeat(" ", defaultMaxBodySize+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(handler, newRequest(huge, "")))
+}
+
+func TestCallbackError(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+
+	calls := 0
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		calls++
+		return errors.New("database unavailable")
+	})
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+
+	signature := aftership.GetHMACSignature(payload, []byte(secret))
+	assert.Equal(t, http.StatusInternalServerError, serve(handler, newRequest(payload, signature)))
+	assert.Equal(t, 1, calls)
+}
+
+func TestUnknownEvent(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		t.Error("unexpected event")
+		return nil
+	})
+
+	body := `{"event": "tracking_deleted", "msg": {}}`
+	assert.Equal(t, http.StatusOK, serve(handler, newRequest(body, aftership.GetHMACSignature(body, []byte(secret)))))
+}
+
+type failingStore struct{}
+
+func (failingStore) Release(ctx context.Context, trackingID string, version Version) error {
+	return errors.New("store unavailable")
+}
+
+func (failingStore) Advance(ctx context.Context, trackingID string, version Version) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func TestDeduplication(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+
+	var checkpoints []int
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		checkpoints = append(checkpoints, len(event.Tracking.Checkpoints))
+		return nil
+	})
+
+	send := func(body string) int {
+		return serve(handler, newRequest(body, aftership.GetHMACSignature(body, []byte(secret))))
+	}
+
+	later := strings.Replace(payload, `"checkpoints": [`,
+		`"checkpoints": [{"slug": "dhl", "tag": "OutForDelivery", "message": "Out for delivery"},`, 1)
+	stale := strings.Replace(payload, "2024-06-11T08:00:00", "2024-06-11T07:00:00", 1)
+
+	assert.Equal(t, http.StatusOK, send(payload))
+	assert.Equal(t, http.StatusOK, send(payload))
+	assert.Equal(t, http.StatusOK, send(later))
+	assert.Equal(t, http.StatusOK, send(stale))
+	assert.Equal(t, http.StatusOK, send(payload))
+	assert.Equal(t, []int{1, 2}, checkpoints)
+
+	// Without deduplication
+	handler = NewHandler([]byte(secret), WithStore(nil))
+	calls := 0
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+	send(payload)
+	send(payload)
+	assert.Equal(t, 2, calls)
+
+	// The store is unavailable
+	handler = NewHandler([]byte(secret), WithStore(failingStore{}))
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		t.Error("unexpected event")
+		return nil
+	})
+	assert.Equal(t, http.StatusInternalServerError, send(payload))
+}
+
+func TestRedeliveryAfterCallbackError(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+
+	calls := 0
+	fail := true
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		calls++
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	signature := aftership.GetHMACSignature(payload, []byte(secret))
+	assert.Equal(t, http.StatusInternalServerError, serve(handler, newRequest(payload, signature)))
+
+	// The retry of AfterShip is dispatched again, then recorded
+	fail = false
+	assert.Equal(t, http.StatusOK, serve(handler, newRequest(payload, signature)))
+	assert.Equal(t, http.StatusOK, serve(handler, newRequest(payload, signature)))
+	assert.Equal(t, 2, calls)
+}
+
+func TestConcurrentOutOfOrderWebhooks(t *testing.T) {
+	handler := NewHandler([]byte(secret))
+
+	var mu sync.Mutex
+	var checkpoints []int
+	dispatched := make(chan struct{})
+	resume := make(chan struct{})
+	handler.OnTrackingUpdated(func(ctx context.Context, event *Event) error {
+		mu.Lock()
+		checkpoints = append(checkpoints, len(event.Tracking.Checkpoints))
+		first := len(checkpoints) == 1
+		mu.Unlock()
+		if first {
+			close(dispatched)
+			<-resume
+		}
+		return nil
+	})
+
+	send := func(body string) int {
+		return serve(handler, newRequest(body, aftership.GetHMACSignature(body, []byte(secret))))
+	}
+	later := strings.Replace(payload, `"checkpoints": [`,
+		`"checkpoints": [{"slug": "dhl", "tag": "OutForDelivery", "message": "Out for delivery"},`, 1)
+
+	// The later update is being delivered while the older one and the duplicates arrive
+	done := make(chan int)
+	go func() {
+		done <- send(later)
+	}()
+	<-dispatched
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, send(payload))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, send(later))
+		}()
+	}
+	wg.Wait()
+	close(resume)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{2}, checkpoints)
+}